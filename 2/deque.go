@@ -0,0 +1,159 @@
+package main
+
+import "fmt"
+
+const minDequeCapacity = 8
+
+// Deque — двусторонняя очередь на кольцевом буфере. Добавление и удаление
+// с обоих концов и доступ по индексу выполняются за O(1).
+// Обычная очередь растёт по мере заполнения, а созданная через NewRingBuffer
+// имеет фиксированную ёмкость и при переполнении вытесняет самый старый элемент.
+// Нулевое значение — пустая растущая очередь.
+type Deque[T any] struct {
+	buf   []T
+	head  int
+	size  int
+	fixed bool
+}
+
+func NewDeque[T any](capacity int) *Deque[T] {
+	return &Deque[T]{
+		buf: make([]T, max(capacity, minDequeCapacity)),
+	}
+}
+
+// NewRingBuffer создаёт очередь, хранящую только последние capacity элементов
+func NewRingBuffer[T any](capacity int) *Deque[T] {
+	if capacity <= 0 {
+		panic("ring buffer capacity must be positive")
+	}
+	return &Deque[T]{
+		buf:   make([]T, capacity),
+		fixed: true,
+	}
+}
+
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+func (d *Deque[T]) Cap() int {
+	return len(d.buf)
+}
+
+func (d *Deque[T]) Full() bool {
+	return d.size == len(d.buf)
+}
+
+// PushBack добавляет элемент в конец. В фиксированном режиме при
+// заполненном буфере вытесняется первый элемент
+func (d *Deque[T]) PushBack(v T) {
+	if d.Full() {
+		if d.fixed {
+			d.buf[d.head] = v
+			d.head = d.index(1)
+			return
+		}
+		d.grow()
+	}
+	d.buf[d.index(d.size)] = v
+	d.size++
+}
+
+// PushFront добавляет элемент в начало. В фиксированном режиме при
+// заполненном буфере вытесняется последний элемент
+func (d *Deque[T]) PushFront(v T) {
+	if d.Full() {
+		if d.fixed {
+			d.head = d.index(-1)
+			d.buf[d.head] = v
+			return
+		}
+		d.grow()
+	}
+	d.head = d.index(-1)
+	d.buf[d.head] = v
+	d.size++
+}
+
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	v := d.buf[d.head]
+	d.buf[d.head] = zero
+	d.head = d.index(1)
+	d.size--
+	return v, true
+}
+
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	i := d.index(d.size - 1)
+	v := d.buf[i]
+	d.buf[i] = zero
+	d.size--
+	return v, true
+}
+
+func (d *Deque[T]) Front() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.head], true
+}
+
+func (d *Deque[T]) Back() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.index(d.size-1)], true
+}
+
+// At возвращает i-й элемент от начала очереди, при выходе за границы паникует как слайс
+func (d *Deque[T]) At(i int) T {
+	d.checkIndex(i)
+	return d.buf[d.index(i)]
+}
+
+func (d *Deque[T]) Set(i int, v T) {
+	d.checkIndex(i)
+	d.buf[d.index(i)] = v
+}
+
+// Values возвращает копию элементов в порядке от начала к концу
+func (d *Deque[T]) Values() []T {
+	res := make([]T, d.size)
+	n := copy(res, d.buf[d.head:min(d.head+d.size, len(d.buf))])
+	copy(res[n:], d.buf[:d.size-n])
+	return res
+}
+
+func (d *Deque[T]) Clear() {
+	clear(d.buf)
+	d.head = 0
+	d.size = 0
+}
+
+func (d *Deque[T]) index(i int) int {
+	return ((d.head+i)%len(d.buf) + len(d.buf)) % len(d.buf)
+}
+
+func (d *Deque[T]) checkIndex(i int) {
+	if i < 0 || i >= d.size {
+		panic(fmt.Sprintf("deque index out of range [%d] with length %d", i, d.size))
+	}
+}
+
+func (d *Deque[T]) grow() {
+	newBuf := make([]T, max(len(d.buf)*2, minDequeCapacity))
+	copy(newBuf, d.Values())
+	d.buf = newBuf
+	d.head = 0
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestDequePushPop проверяет добавление и извлечение с обоих концов
+func TestDequePushPop(t *testing.T) {
+	d := NewDeque[int](0)
+
+	d.PushBack(2)
+	d.PushBack(3)
+	d.PushFront(1)
+	d.PushFront(0)
+
+	if got := d.Values(); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Fatalf("Expected [0 1 2 3], got %v", got)
+	}
+
+	if v, ok := d.PopFront(); !ok || v != 0 {
+		t.Errorf("PopFront: expected 0, got %d (ok: %v)", v, ok)
+	}
+	if v, ok := d.PopBack(); !ok || v != 3 {
+		t.Errorf("PopBack: expected 3, got %d (ok: %v)", v, ok)
+	}
+	if d.Len() != 2 {
+		t.Errorf("Expected length 2, got %d", d.Len())
+	}
+}
+
+func TestDequeEmpty(t *testing.T) {
+	d := NewDeque[string](4)
+
+	if _, ok := d.PopFront(); ok {
+		t.Error("PopFront on empty deque returned ok")
+	}
+	if _, ok := d.PopBack(); ok {
+		t.Error("PopBack on empty deque returned ok")
+	}
+	if _, ok := d.Front(); ok {
+		t.Error("Front on empty deque returned ok")
+	}
+	if _, ok := d.Back(); ok {
+		t.Error("Back on empty deque returned ok")
+	}
+}
+
+// TestDequeZeroValue проверяет, что нулевое значение работает без конструктора
+func TestDequeZeroValue(t *testing.T) {
+	var back, front Deque[int]
+	if _, ok := back.PopFront(); ok || back.Len() != 0 {
+		t.Error("Zero deque should be empty")
+	}
+	for i := range 20 {
+		back.PushBack(i)
+		front.PushFront(i)
+	}
+	if back.Len() != 20 || back.Values()[19] != 19 || front.Values()[0] != 19 {
+		t.Errorf("Unexpected values %v and %v", back.Values(), front.Values())
+	}
+}
+
+// TestDequeGrow проверяет рост буфера, когда начало очереди не в нулевой позиции
+func TestDequeGrow(t *testing.T) {
+	d := NewDeque[int](0)
+	var expected []int
+
+	for i := range 20 {
+		if i%2 == 0 {
+			d.PushFront(i)
+			expected = slices.Insert(expected, 0, i)
+		} else {
+			d.PushBack(i)
+			expected = append(expected, i)
+		}
+	}
+
+	if got := d.Values(); !slices.Equal(got, expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i, v := range expected {
+		if d.At(i) != v {
+			t.Errorf("At(%d): expected %d, got %d", i, v, d.At(i))
+		}
+	}
+	if d.Cap() < 20 {
+		t.Errorf("Expected capacity >= 20, got %d", d.Cap())
+	}
+}
+
+func TestDequeSetAndOutOfRange(t *testing.T) {
+	d := NewDeque[int](0)
+	d.PushBack(1)
+	d.Set(0, 10)
+	if d.At(0) != 10 {
+		t.Errorf("Expected 10 after Set, got %d", d.At(0))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on out of range index")
+		}
+	}()
+	d.At(1)
+}
+
+// TestRingBufferOverwrite проверяет вытеснение самых старых элементов
+func TestRingBufferOverwrite(t *testing.T) {
+	r := NewRingBuffer[int](3)
+
+	for i := 1; i <= 5; i++ {
+		r.PushBack(i)
+	}
+
+	if got := r.Values(); !slices.Equal(got, []int{3, 4, 5}) {
+		t.Errorf("Expected last 3 samples [3 4 5], got %v", got)
+	}
+	if r.Cap() != 3 || !r.Full() {
+		t.Errorf("Ring buffer should stay full with capacity 3, got cap %d len %d", r.Cap(), r.Len())
+	}
+
+	r.PushFront(0)
+	if got := r.Values(); !slices.Equal(got, []int{0, 3, 4}) {
+		t.Errorf("Expected [0 3 4] after PushFront, got %v", got)
+	}
+
+	r.Clear()
+	if r.Len() != 0 {
+		t.Errorf("Expected empty buffer after Clear, got %d", r.Len())
+	}
+}
+
+func BenchmarkDequePopFront(b *testing.B) {
+	d := NewDeque[int](benchSize)
+	for b.Loop() {
+		d.PushBack(1)
+		d.PopFront()
+	}
+}
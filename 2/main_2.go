@@ -33,6 +33,31 @@ func main() {
 	fmt.Println(removeElement(nums, 4))
 	fmt.Println("Исходный слайс:")
 	fmt.Println(nums)
+
+	fmt.Println("\nСтатистика по слайсу:")
+	printStats(nums)
+}
+
+func printStats(nums []int) {
+	mean, _ := Mean(nums)
+	median, _ := Median(nums)
+	modes, _ := Mode(nums)
+	stdDev, _ := StdDev(nums)
+	p90, _ := Percentile(nums, 90, PercentileLinear)
+	lo, hi, _ := MinMax(nums)
+	fmt.Printf("Среднее: %.2f, медиана: %.2f, мода: %v\n", mean, median, modes)
+	fmt.Printf("Стандартное отклонение: %.2f, 90-й перцентиль: %.2f\n", stdDev, p90)
+	fmt.Printf("Минимум: %d, максимум: %d\n", lo, hi)
+
+	hist, err := Histogram(nums, 5)
+	if err != nil {
+		fmt.Println("Гистограмма:", err)
+		return
+	}
+	fmt.Println("Гистограмма:")
+	for _, b := range hist {
+		fmt.Printf("[%6.2f, %6.2f): %d\n", b.Low, b.High, b.Count)
+	}
 }
 
 func initSlice() []int {
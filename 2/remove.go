@@ -0,0 +1,38 @@
+package main
+
+// Функции ниже, в отличие от removeElement, не копируют слайс и работают
+// с тем же базовым массивом. Переданный слайс после вызова использовать
+// нельзя: его элементы сдвинуты, а хвост за новой длиной обнулён, чтобы
+// удалённые указатели не удерживали память. Работать дальше нужно только
+// с возвращённым слайсом, как с результатом append.
+
+// removeElementInPlace удаляет элемент по индексу со сдвигом хвоста, порядок сохраняется. O(n)
+func removeElementInPlace[T any](nums []T, indx int) []T {
+	copy(nums[indx:], nums[indx+1:])
+	var zero T
+	nums[len(nums)-1] = zero
+	return nums[:len(nums)-1]
+}
+
+// removeElementUnordered ставит на место удаляемого элемента последний. O(1), порядок не сохраняется
+func removeElementUnordered[T any](nums []T, indx int) []T {
+	last := len(nums) - 1
+	nums[indx] = nums[last]
+	var zero T
+	nums[last] = zero
+	return nums[:last]
+}
+
+// removeIf удаляет за один проход все элементы, для которых remove вернул true,
+// порядок оставшихся сохраняется. O(n)
+func removeIf[T any](nums []T, remove func(T) bool) []T {
+	n := 0
+	for _, v := range nums {
+		if !remove(v) {
+			nums[n] = v
+			n++
+		}
+	}
+	clear(nums[n:])
+	return nums[:n]
+}
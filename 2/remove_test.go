@@ -0,0 +1,194 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRemoveElementInPlace(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []int
+		index    int
+		expected []int
+	}{
+		{
+			name:     "remove first element",
+			input:    []int{1, 2, 3, 4, 5},
+			index:    0,
+			expected: []int{2, 3, 4, 5},
+		},
+		{
+			name:     "remove last element",
+			input:    []int{1, 2, 3, 4, 5},
+			index:    4,
+			expected: []int{1, 2, 3, 4},
+		},
+		{
+			name:     "remove middle element",
+			input:    []int{1, 2, 3, 4, 5},
+			index:    2,
+			expected: []int{1, 2, 4, 5},
+		},
+		{
+			name:     "remove from single element slice",
+			input:    []int{42},
+			index:    0,
+			expected: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backing := tt.input
+			result := removeElementInPlace(tt.input, tt.index)
+
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+			// Освободившийся хвост должен быть обнулён
+			if backing[len(backing)-1] != 0 {
+				t.Errorf("Tail was not zeroed: %v", backing)
+			}
+		})
+	}
+}
+
+func TestRemoveElementUnordered(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []int
+		index    int
+		expected []int
+	}{
+		{
+			name:     "remove first element",
+			input:    []int{1, 2, 3, 4, 5},
+			index:    0,
+			expected: []int{5, 2, 3, 4},
+		},
+		{
+			name:     "remove last element",
+			input:    []int{1, 2, 3, 4, 5},
+			index:    4,
+			expected: []int{1, 2, 3, 4},
+		},
+		{
+			name:     "remove from single element slice",
+			input:    []int{42},
+			index:    0,
+			expected: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backing := tt.input
+			result := removeElementUnordered(tt.input, tt.index)
+
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+			if backing[len(backing)-1] != 0 {
+				t.Errorf("Tail was not zeroed: %v", backing)
+			}
+		})
+	}
+}
+
+func TestRemoveIf(t *testing.T) {
+	isOdd := func(n int) bool { return n%2 != 0 }
+
+	tests := []struct {
+		name     string
+		input    []int
+		expected []int
+	}{
+		{name: "mixed numbers", input: []int{1, 2, 3, 4, 5, 6}, expected: []int{2, 4, 6}},
+		{name: "nothing to remove", input: []int{2, 4}, expected: []int{2, 4}},
+		{name: "remove everything", input: []int{1, 3}, expected: []int{}},
+		{name: "empty slice", input: []int{}, expected: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backing := tt.input
+			result := removeIf(tt.input, isOdd)
+
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+			for i := len(result); i < len(backing); i++ {
+				if backing[i] != 0 {
+					t.Errorf("Tail was not zeroed at index %d: %v", i, backing)
+				}
+			}
+		})
+	}
+}
+
+// TestRemoveInPlaceReleasesPointers проверяет, что удалённые указатели не остаются в базовом массиве
+func TestRemoveInPlaceReleasesPointers(t *testing.T) {
+	a, b, c := new(int), new(int), new(int)
+	ptrs := []*int{a, b, c}
+
+	result := removeElementInPlace(ptrs, 0)
+	if ptrs[2] != nil {
+		t.Error("Pointer left in tail after removeElementInPlace")
+	}
+	result = removeElementUnordered(result, 0)
+	if ptrs[1] != nil {
+		t.Error("Pointer left in tail after removeElementUnordered")
+	}
+	if len(result) != 1 || result[0] != c {
+		t.Errorf("Unexpected result %v", result)
+	}
+}
+
+const benchSize = 1024
+
+func benchInput() []int {
+	nums := make([]int, benchSize)
+	for i := range nums {
+		nums[i] = i
+	}
+	return nums
+}
+
+func BenchmarkRemoveElement(b *testing.B) {
+	nums := benchInput()
+	for b.Loop() {
+		_ = removeElement(nums, benchSize/2)
+	}
+}
+
+func BenchmarkRemoveElementInPlace(b *testing.B) {
+	src := benchInput()
+	nums := slices.Clone(src)
+	for b.Loop() {
+		if len(nums) == 1 {
+			nums = append(nums[:0], src...)
+		}
+		nums = removeElementInPlace(nums, len(nums)/2)
+	}
+}
+
+func BenchmarkRemoveElementUnordered(b *testing.B) {
+	src := benchInput()
+	nums := slices.Clone(src)
+	for b.Loop() {
+		if len(nums) == 1 {
+			nums = append(nums[:0], src...)
+		}
+		nums = removeElementUnordered(nums, len(nums)/2)
+	}
+}
+
+func BenchmarkRemoveIf(b *testing.B) {
+	src := benchInput()
+	nums := make([]int, benchSize)
+	for b.Loop() {
+		nums = append(nums[:0], src...)
+		_ = removeIf(nums, func(n int) bool { return n%2 != 0 })
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+var (
+	ErrEmptySlice        = errors.New("stats: empty slice")
+	ErrTooFewValues      = errors.New("stats: at least two values required")
+	ErrInvalidPercentile = errors.New("stats: percentile must be in [0, 100]")
+	ErrInvalidMethod     = errors.New("stats: unknown percentile method")
+	ErrInvalidBins       = errors.New("stats: invalid histogram bins")
+)
+
+// PercentileMethod задаёт способ интерполяции, когда перцентиль попадает между двумя элементами
+type PercentileMethod int
+
+const (
+	PercentileLinear   PercentileMethod = iota // линейная интерполяция между соседями
+	PercentileLower                            // меньший из соседей
+	PercentileHigher                           // больший из соседей
+	PercentileNearest                          // ближайший из соседей
+	PercentileMidpoint                         // среднее двух соседей
+)
+
+type Bin struct {
+	Low   float64
+	High  float64
+	Count int
+}
+
+func Mean[T Number](nums []T) (float64, error) {
+	if len(nums) == 0 {
+		return 0, ErrEmptySlice
+	}
+	// Инкрементальное среднее не переполняется на больших суммах
+	var mean float64
+	for i, n := range nums {
+		mean += (float64(n) - mean) / float64(i+1)
+	}
+	return mean, nil
+}
+
+func Median[T Number](nums []T) (float64, error) {
+	return Percentile(nums, 50, PercentileMidpoint)
+}
+
+// Mode возвращает все значения с максимальной частотой в порядке возрастания
+func Mode[T Number](nums []T) ([]T, error) {
+	if len(nums) == 0 {
+		return nil, ErrEmptySlice
+	}
+	counts := make(map[T]int)
+	maxCount := 0
+	for _, n := range nums {
+		counts[n]++
+		maxCount = max(maxCount, counts[n])
+	}
+	var modes []T
+	for n, c := range counts {
+		if c == maxCount {
+			modes = append(modes, n)
+		}
+	}
+	slices.Sort(modes)
+	return modes, nil
+}
+
+// welford считает среднее и сумму квадратов отклонений за один проход
+func welford[T Number](nums []T) (mean, m2 float64) {
+	for i, n := range nums {
+		x := float64(n)
+		delta := x - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (x - mean)
+	}
+	return mean, m2
+}
+
+// Variance возвращает дисперсию генеральной совокупности
+func Variance[T Number](nums []T) (float64, error) {
+	if len(nums) == 0 {
+		return 0, ErrEmptySlice
+	}
+	_, m2 := welford(nums)
+	return m2 / float64(len(nums)), nil
+}
+
+// SampleVariance возвращает выборочную (несмещённую) дисперсию. Для одного
+// значения она не определена, тогда возвращается ErrTooFewValues
+func SampleVariance[T Number](nums []T) (float64, error) {
+	if len(nums) == 0 {
+		return 0, ErrEmptySlice
+	}
+	if len(nums) < 2 {
+		return 0, ErrTooFewValues
+	}
+	_, m2 := welford(nums)
+	return m2 / float64(len(nums)-1), nil
+}
+
+func StdDev[T Number](nums []T) (float64, error) {
+	v, err := Variance(nums)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(v), nil
+}
+
+func SampleStdDev[T Number](nums []T) (float64, error) {
+	v, err := SampleVariance(nums)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(v), nil
+}
+
+func Percentile[T Number](nums []T, p float64, method PercentileMethod) (float64, error) {
+	if len(nums) == 0 {
+		return 0, ErrEmptySlice
+	}
+	if p < 0 || p > 100 || math.IsNaN(p) {
+		return 0, ErrInvalidPercentile
+	}
+
+	sorted := make([]float64, len(nums))
+	for i, n := range nums {
+		sorted[i] = float64(n)
+	}
+	slices.Sort(sorted)
+
+	pos := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	frac := pos - float64(lo)
+
+	switch method {
+	case PercentileLinear:
+		return sorted[lo] + (sorted[hi]-sorted[lo])*frac, nil
+	case PercentileLower:
+		return sorted[lo], nil
+	case PercentileHigher:
+		return sorted[hi], nil
+	case PercentileNearest:
+		if frac < 0.5 || (frac == 0.5 && lo%2 == 0) {
+			return sorted[lo], nil
+		}
+		return sorted[hi], nil
+	case PercentileMidpoint:
+		return (sorted[lo] + sorted[hi]) / 2, nil
+	}
+	return 0, ErrInvalidMethod
+}
+
+func MinMax[T Number](nums []T) (T, T, error) {
+	if len(nums) == 0 {
+		var zero T
+		return zero, zero, ErrEmptySlice
+	}
+	return slices.Min(nums), slices.Max(nums), nil
+}
+
+// Histogram делит диапазон [min, max] на bins интервалов одинаковой ширины
+func Histogram[T Number](nums []T, bins int) ([]Bin, error) {
+	if len(nums) == 0 {
+		return nil, ErrEmptySlice
+	}
+	if bins <= 0 {
+		return nil, ErrInvalidBins
+	}
+	lo, hi := float64(slices.Min(nums)), float64(slices.Max(nums))
+	if lo == hi {
+		hi = lo + 1
+	}
+	width := (hi - lo) / float64(bins)
+	edges := make([]float64, bins+1)
+	for i := range edges {
+		edges[i] = lo + width*float64(i)
+	}
+	edges[bins] = hi
+	return HistogramEdges(nums, edges)
+}
+
+// HistogramEdges раскладывает значения по интервалам [edges[i], edges[i+1]),
+// последний интервал включает правую границу. Значения вне границ не учитываются
+func HistogramEdges[T Number](nums []T, edges []float64) ([]Bin, error) {
+	if len(nums) == 0 {
+		return nil, ErrEmptySlice
+	}
+	if len(edges) < 2 || !slices.IsSorted(edges) {
+		return nil, ErrInvalidBins
+	}
+	for i := 1; i < len(edges); i++ {
+		if edges[i] == edges[i-1] {
+			return nil, ErrInvalidBins
+		}
+	}
+
+	hist := make([]Bin, len(edges)-1)
+	for i := range hist {
+		hist[i] = Bin{Low: edges[i], High: edges[i+1]}
+	}
+	last := len(hist) - 1
+	for _, n := range nums {
+		x := float64(n)
+		if x < edges[0] || x > edges[last+1] {
+			continue
+		}
+		i, found := slices.BinarySearch(edges, x)
+		if !found {
+			i--
+		}
+		hist[min(i, last)].Count++
+	}
+	return hist, nil
+}
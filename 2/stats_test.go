@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// TestEmptyInput проверяет, что все функции возвращают ошибку на пустом слайсе
+func TestEmptyInput(t *testing.T) {
+	var empty []int
+	checks := map[string]error{}
+
+	_, checks["Mean"] = Mean(empty)
+	_, checks["Median"] = Median(empty)
+	_, checks["Mode"] = Mode(empty)
+	_, checks["Variance"] = Variance(empty)
+	_, checks["SampleVariance"] = SampleVariance(empty)
+	_, checks["StdDev"] = StdDev(empty)
+	_, checks["Percentile"] = Percentile(empty, 50, PercentileLinear)
+	_, _, checks["MinMax"] = MinMax(empty)
+	_, checks["Histogram"] = Histogram(empty, 3)
+
+	for name, err := range checks {
+		if !errors.Is(err, ErrEmptySlice) {
+			t.Errorf("%s: expected ErrEmptySlice, got %v", name, err)
+		}
+	}
+}
+
+func TestSampleVarianceSingleValue(t *testing.T) {
+	if _, err := SampleVariance([]int{1}); !errors.Is(err, ErrTooFewValues) {
+		t.Errorf("SampleVariance: expected ErrTooFewValues, got %v", err)
+	}
+	if _, err := SampleStdDev([]float64{2.5}); !errors.Is(err, ErrTooFewValues) {
+		t.Errorf("SampleStdDev: expected ErrTooFewValues, got %v", err)
+	}
+}
+
+func TestMeanAndMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []float64
+		mean   float64
+		median float64
+	}{
+		{name: "single element", input: []float64{5}, mean: 5, median: 5},
+		{name: "odd length", input: []float64{3, 1, 2}, mean: 2, median: 2},
+		{name: "even length", input: []float64{4, 1, 3, 2}, mean: 2.5, median: 2.5},
+		{name: "negatives", input: []float64{-1, -2, -3, 10}, mean: 1, median: -1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mean, err := Mean(tt.input)
+			if err != nil || !almostEqual(mean, tt.mean) {
+				t.Errorf("Mean = %v, %v; expected %v", mean, err, tt.mean)
+			}
+			median, err := Median(tt.input)
+			if err != nil || !almostEqual(median, tt.median) {
+				t.Errorf("Median = %v, %v; expected %v", median, err, tt.median)
+			}
+		})
+	}
+}
+
+func TestMode(t *testing.T) {
+	modes, err := Mode([]int{1, 2, 2, 3, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(modes, []int{2, 3}) {
+		t.Errorf("Expected [2 3], got %v", modes)
+	}
+}
+
+// TestVarianceStable проверяет устойчивость дисперсии при большом смещении значений
+func TestVarianceStable(t *testing.T) {
+	input := []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}
+
+	v, err := Variance(input)
+	if err != nil || !almostEqual(v, 22.5) {
+		t.Errorf("Variance = %v, %v; expected 22.5", v, err)
+	}
+	sv, err := SampleVariance(input)
+	if err != nil || !almostEqual(sv, 30) {
+		t.Errorf("SampleVariance = %v, %v; expected 30", sv, err)
+	}
+	sd, err := StdDev([]int{2, 4, 4, 4, 5, 5, 7, 9})
+	if err != nil || !almostEqual(sd, 2) {
+		t.Errorf("StdDev = %v, %v; expected 2", sd, err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	input := []int{10, 20, 30, 40}
+	tests := []struct {
+		method   PercentileMethod
+		p        float64
+		expected float64
+	}{
+		{PercentileLinear, 50, 25},
+		{PercentileLinear, 0, 10},
+		{PercentileLinear, 100, 40},
+		{PercentileLinear, 40, 22},
+		{PercentileLower, 40, 20},
+		{PercentileHigher, 40, 30},
+		{PercentileNearest, 40, 20},
+		{PercentileNearest, 60, 30},
+		{PercentileMidpoint, 40, 25},
+	}
+
+	for _, tt := range tests {
+		got, err := Percentile(input, tt.p, tt.method)
+		if err != nil || !almostEqual(got, tt.expected) {
+			t.Errorf("Percentile(p=%v, method=%d) = %v, %v; expected %v", tt.p, tt.method, got, err, tt.expected)
+		}
+	}
+
+	if _, err := Percentile(input, 101, PercentileLinear); !errors.Is(err, ErrInvalidPercentile) {
+		t.Errorf("Expected ErrInvalidPercentile, got %v", err)
+	}
+	if _, err := Percentile(input, 50, PercentileMethod(42)); !errors.Is(err, ErrInvalidMethod) {
+		t.Errorf("Expected ErrInvalidMethod, got %v", err)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	lo, hi, err := MinMax([]int{5, -3, 8, 0})
+	if err != nil || lo != -3 || hi != 8 {
+		t.Errorf("MinMax = %d, %d, %v; expected -3, 8", lo, hi, err)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	hist, err := Histogram([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 10}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{2, 2, 2, 2, 2}
+	for i, b := range hist {
+		if b.Count != expected[i] {
+			t.Errorf("Bin %d [%v, %v): expected %d, got %d", i, b.Low, b.High, expected[i], b.Count)
+		}
+	}
+
+	// Все значения одинаковые — один ненулевой интервал
+	hist, err = Histogram([]int{3, 3, 3}, 2)
+	if err != nil || hist[0].Count != 3 {
+		t.Errorf("Expected all values in first bin, got %v, %v", hist, err)
+	}
+
+	if _, err := Histogram([]int{1}, 0); !errors.Is(err, ErrInvalidBins) {
+		t.Errorf("Expected ErrInvalidBins, got %v", err)
+	}
+}
+
+func TestHistogramEdges(t *testing.T) {
+	hist, err := HistogramEdges([]float64{-1, 0, 0.5, 1, 5, 9.99, 10, 11}, []float64{0, 1, 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hist[0].Count != 2 || hist[1].Count != 4 {
+		t.Errorf("Expected counts [2 4], got [%d %d]", hist[0].Count, hist[1].Count)
+	}
+
+	if _, err := HistogramEdges([]int{1}, []float64{1, 0}); !errors.Is(err, ErrInvalidBins) {
+		t.Errorf("Expected ErrInvalidBins for unsorted edges, got %v", err)
+	}
+}
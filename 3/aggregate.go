@@ -0,0 +1,100 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Функции ниже работают с любой StringIntMapInterface через один вызов Copy,
+// поэтому для потокобезопасных мап видят согласованный снимок
+
+// Sum возвращает сумму всех значений, 0 для пустой мапы
+func Sum(m StringIntMapInterface) int {
+	sum := 0
+	for _, v := range m.Copy() {
+		sum += v
+	}
+	return sum
+}
+
+// Min возвращает пару с наименьшим значением, при равенстве — с меньшим ключом.
+// ok == false для пустой мапы
+func Min(m StringIntMapInterface) (Entry, bool) {
+	return extreme(m, -1)
+}
+
+// Max возвращает пару с наибольшим значением, при равенстве — с меньшим ключом.
+// ok == false для пустой мапы
+func Max(m StringIntMapInterface) (Entry, bool) {
+	return extreme(m, 1)
+}
+
+func extreme(m StringIntMapInterface, sign int) (Entry, bool) {
+	var best Entry
+	found := false
+	for k, v := range m.Copy() {
+		c := cmp.Compare(v, best.Value) * sign
+		if !found || c > 0 || c == 0 && k < best.Key {
+			best, found = Entry{Key: k, Value: v}, true
+		}
+	}
+	return best, found
+}
+
+// Mean возвращает среднее значение; сумма считается во float64, чтобы не переполниться.
+// ok == false для пустой мапы
+func Mean(m StringIntMapInterface) (float64, bool) {
+	data := m.Copy()
+	if len(data) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, v := range data {
+		sum += float64(v)
+	}
+	return sum / float64(len(data)), true
+}
+
+// TopN возвращает до n пар с наибольшими значениями по убыванию,
+// равные значения упорядочены по возрастанию ключа
+func TopN(m StringIntMapInterface, n int) []Entry {
+	if n <= 0 {
+		return nil
+	}
+	data := m.Copy()
+	entries := make([]Entry, 0, len(data))
+	for k, v := range data {
+		entries = append(entries, Entry{Key: k, Value: v})
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		if c := cmp.Compare(b.Value, a.Value); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return entries[:min(n, len(entries))]
+}
+
+// Filter возвращает новую мапу с парами, для которых pred вернул true
+func Filter(m StringIntMapInterface, pred func(key string, value int) bool) *StringIntMap {
+	res := NewStringIntMap()
+	for k, v := range m.Copy() {
+		if pred(k, v) {
+			res.Add(k, v)
+		}
+	}
+	return res
+}
+
+// GroupBy возвращает новую мапу, где значения просуммированы по ключу keyFn(key)
+func GroupBy(m StringIntMapInterface, keyFn func(key string) string) *StringIntMap {
+	groups := make(map[string]int)
+	for k, v := range m.Copy() {
+		groups[keyFn(k)] += v
+	}
+	res := NewStringIntMap()
+	for k, v := range groups {
+		res.Add(k, v)
+	}
+	return res
+}
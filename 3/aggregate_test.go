@@ -0,0 +1,110 @@
+package main
+
+import (
+	"maps"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSumMinMaxMean(t *testing.T) {
+	m := stringIntMapFrom(map[string]int{"a": 3, "b": -1, "c": 7, "d": -1, "e": 7})
+
+	if s := Sum(m); s != 15 {
+		t.Errorf("Sum: expected 15, got %d", s)
+	}
+	// При равных значениях выбирается меньший ключ
+	if e, ok := Min(m); !ok || e != (Entry{Key: "b", Value: -1}) {
+		t.Errorf("Min: expected {b -1}, got %v (ok: %v)", e, ok)
+	}
+	if e, ok := Max(m); !ok || e != (Entry{Key: "c", Value: 7}) {
+		t.Errorf("Max: expected {c 7}, got %v (ok: %v)", e, ok)
+	}
+	if mean, ok := Mean(m); !ok || mean != 3 {
+		t.Errorf("Mean: expected 3, got %v (ok: %v)", mean, ok)
+	}
+}
+
+func TestAggregatesEmpty(t *testing.T) {
+	m := NewStringIntMap()
+	if Sum(m) != 0 {
+		t.Error("Sum of empty map should be 0")
+	}
+	if _, ok := Min(m); ok {
+		t.Error("Min of empty map should not be ok")
+	}
+	if _, ok := Max(m); ok {
+		t.Error("Max of empty map should not be ok")
+	}
+	if _, ok := Mean(m); ok {
+		t.Error("Mean of empty map should not be ok")
+	}
+	if top := TopN(m, 3); len(top) != 0 {
+		t.Errorf("TopN of empty map: expected none, got %v", top)
+	}
+}
+
+func TestMinMaxNegativeOnly(t *testing.T) {
+	m := stringIntMapFrom(map[string]int{"x": -5, "y": -2})
+	if e, _ := Max(m); e.Key != "y" {
+		t.Errorf("Max: expected y, got %v", e)
+	}
+	if e, _ := Min(m); e.Key != "x" {
+		t.Errorf("Min: expected x, got %v", e)
+	}
+}
+
+func TestTopN(t *testing.T) {
+	m := stringIntMapFrom(map[string]int{"a": 1, "b": 5, "c": 3, "d": 5})
+
+	tests := []struct {
+		n        int
+		expected []Entry
+	}{
+		{0, nil},
+		{1, []Entry{{"b", 5}}},
+		{3, []Entry{{"b", 5}, {"d", 5}, {"c", 3}}},
+		{10, []Entry{{"b", 5}, {"d", 5}, {"c", 3}, {"a", 1}}},
+	}
+	for _, tt := range tests {
+		if got := TopN(m, tt.n); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("TopN(%d): expected %v, got %v", tt.n, tt.expected, got)
+		}
+	}
+}
+
+func TestFilterAndGroupBy(t *testing.T) {
+	m := stringIntMapFrom(map[string]int{"user:1": 1, "user:2": 2, "admin:1": 10, "misc": 4})
+
+	positive := Filter(m, func(key string, value int) bool { return value > 1 })
+	expected := map[string]int{"user:2": 2, "admin:1": 10, "misc": 4}
+	if !maps.Equal(positive.Copy(), expected) {
+		t.Errorf("Filter: expected %v, got %v", expected, positive.Copy())
+	}
+	if !m.Exists("user:1") {
+		t.Error("Filter must not modify the source map")
+	}
+
+	byKind := GroupBy(m, func(key string) string {
+		kind, _, _ := strings.Cut(key, ":")
+		return kind
+	})
+	expected = map[string]int{"user": 3, "admin": 10, "misc": 4}
+	if !maps.Equal(byKind.Copy(), expected) {
+		t.Errorf("GroupBy: expected %v, got %v", expected, byKind.Copy())
+	}
+}
+
+// TestAggregatesOverWrappers проверяет, что функции работают с любой реализацией интерфейса
+func TestAggregatesOverWrappers(t *testing.T) {
+	m := NewShardedStringIntMap(4)
+	for i, k := range []string{"a", "b", "c"} {
+		m.Add(k, i+1)
+	}
+	if Sum(m) != 6 {
+		t.Errorf("Sum over sharded map: expected 6, got %d", Sum(m))
+	}
+	if e, _ := Max(m); e.Key != "c" {
+		t.Errorf("Max over sharded map: expected c, got %v", e)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import "container/list"
+
+type Entry struct {
+	Key   string
+	Value int
+}
+
+// EvictFunc вызывается для каждого вытесненного по ёмкости ключа
+type EvictFunc func(key string, value int)
+
+// LRUStringIntMap хранит не больше capacity ключей и при переполнении
+// вытесняет ключ, к которому дольше всего не обращались через Get или Add
+type LRUStringIntMap struct {
+	capacity int
+	onEvict  EvictFunc
+	items    map[string]*list.Element
+	order    *list.List // от недавних к давним, значения — *Entry
+}
+
+func NewLRUStringIntMap(capacity int, onEvict EvictFunc) *LRUStringIntMap {
+	if capacity <= 0 {
+		panic("LRU capacity must be positive")
+	}
+	return &LRUStringIntMap{
+		capacity: capacity,
+		onEvict:  onEvict,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (m *LRUStringIntMap) Add(key string, value int) {
+	if el, ok := m.items[key]; ok {
+		el.Value.(*Entry).Value = value
+		m.order.MoveToFront(el)
+		return
+	}
+	if m.order.Len() >= m.capacity {
+		oldest := m.order.Back()
+		e := m.order.Remove(oldest).(*Entry)
+		delete(m.items, e.Key)
+		if m.onEvict != nil {
+			m.onEvict(e.Key, e.Value)
+		}
+	}
+	m.items[key] = m.order.PushFront(&Entry{Key: key, Value: value})
+}
+
+func (m *LRUStringIntMap) Remove(key string) {
+	if el, ok := m.items[key]; ok {
+		m.order.Remove(el)
+		delete(m.items, key)
+	}
+}
+
+func (m *LRUStringIntMap) Copy() map[string]int {
+	newMap := make(map[string]int, len(m.items))
+	for k, el := range m.items {
+		newMap[k] = el.Value.(*Entry).Value
+	}
+	return newMap
+}
+
+// Exists не считается обращением и не меняет порядок вытеснения
+func (m *LRUStringIntMap) Exists(key string) bool {
+	_, ok := m.items[key]
+	return ok
+}
+
+func (m *LRUStringIntMap) Get(key string) (int, bool) {
+	el, ok := m.items[key]
+	if !ok {
+		return 0, false
+	}
+	m.order.MoveToFront(el)
+	return el.Value.(*Entry).Value, true
+}
+
+func (m *LRUStringIntMap) Len() int {
+	return m.order.Len()
+}
+
+// Entries возвращает записи от самой недавней к самой давней. Copy возвращает
+// обычную мапу, поэтому порядок доступен только здесь
+func (m *LRUStringIntMap) Entries() []Entry {
+	res := make([]Entry, 0, m.order.Len())
+	for el := m.order.Front(); el != nil; el = el.Next() {
+		res = append(res, *el.Value.(*Entry))
+	}
+	return res
+}
+
+type lfuEntry struct {
+	Entry
+	bucket *list.Element // элемент LFUStringIntMap.buckets
+}
+
+type lfuBucket struct {
+	freq    int
+	entries *list.List // от недавних к давним, значения — *lfuEntry
+}
+
+// LFUStringIntMap хранит не больше capacity ключей и при переполнении
+// вытесняет самый редко используемый ключ, среди равных — самый давний.
+// Ключи разложены по спискам частот, упорядоченным по возрастанию,
+// поэтому все операции O(1)
+type LFUStringIntMap struct {
+	capacity int
+	onEvict  EvictFunc
+	items    map[string]*list.Element
+	buckets  *list.List // значения — *lfuBucket
+}
+
+func NewLFUStringIntMap(capacity int, onEvict EvictFunc) *LFUStringIntMap {
+	if capacity <= 0 {
+		panic("LFU capacity must be positive")
+	}
+	return &LFUStringIntMap{
+		capacity: capacity,
+		onEvict:  onEvict,
+		items:    make(map[string]*list.Element),
+		buckets:  list.New(),
+	}
+}
+
+func (m *LFUStringIntMap) Add(key string, value int) {
+	if el, ok := m.items[key]; ok {
+		el.Value.(*lfuEntry).Value = value
+		m.touch(el)
+		return
+	}
+	if len(m.items) >= m.capacity {
+		m.evict()
+	}
+	first := m.buckets.Front()
+	if first == nil || first.Value.(*lfuBucket).freq != 1 {
+		first = m.buckets.PushFront(&lfuBucket{freq: 1, entries: list.New()})
+	}
+	m.items[key] = m.push(first, &lfuEntry{Entry: Entry{Key: key, Value: value}})
+}
+
+func (m *LFUStringIntMap) Remove(key string) {
+	if el, ok := m.items[key]; ok {
+		m.unlink(el)
+		delete(m.items, key)
+	}
+}
+
+func (m *LFUStringIntMap) Copy() map[string]int {
+	newMap := make(map[string]int, len(m.items))
+	for k, el := range m.items {
+		newMap[k] = el.Value.(*lfuEntry).Value
+	}
+	return newMap
+}
+
+// Exists не считается обращением и не меняет частоту ключа
+func (m *LFUStringIntMap) Exists(key string) bool {
+	_, ok := m.items[key]
+	return ok
+}
+
+func (m *LFUStringIntMap) Get(key string) (int, bool) {
+	el, ok := m.items[key]
+	if !ok {
+		return 0, false
+	}
+	m.touch(el)
+	return el.Value.(*lfuEntry).Value, true
+}
+
+func (m *LFUStringIntMap) Len() int {
+	return len(m.items)
+}
+
+// Entries возвращает записи от самых часто используемых к самым редким,
+// при равной частоте — от недавних к давним
+func (m *LFUStringIntMap) Entries() []Entry {
+	res := make([]Entry, 0, len(m.items))
+	for b := m.buckets.Back(); b != nil; b = b.Prev() {
+		for el := b.Value.(*lfuBucket).entries.Front(); el != nil; el = el.Next() {
+			res = append(res, el.Value.(*lfuEntry).Entry)
+		}
+	}
+	return res
+}
+
+func (m *LFUStringIntMap) push(bucket *list.Element, e *lfuEntry) *list.Element {
+	e.bucket = bucket
+	return bucket.Value.(*lfuBucket).entries.PushFront(e)
+}
+
+// unlink убирает элемент из списка его частоты, пустые списки удаляются
+func (m *LFUStringIntMap) unlink(el *list.Element) {
+	bucket := el.Value.(*lfuEntry).bucket
+	b := bucket.Value.(*lfuBucket)
+	b.entries.Remove(el)
+	if b.entries.Len() == 0 {
+		m.buckets.Remove(bucket)
+	}
+}
+
+// touch переносит элемент в список следующей частоты
+func (m *LFUStringIntMap) touch(el *list.Element) {
+	e := el.Value.(*lfuEntry)
+	cur := e.bucket
+	freq := cur.Value.(*lfuBucket).freq + 1
+
+	next := cur.Next()
+	if next == nil || next.Value.(*lfuBucket).freq != freq {
+		next = m.buckets.InsertAfter(&lfuBucket{freq: freq, entries: list.New()}, cur)
+	}
+	m.unlink(el)
+	m.items[e.Key] = m.push(next, e)
+}
+
+func (m *LFUStringIntMap) evict() {
+	b := m.buckets.Front().Value.(*lfuBucket)
+	el := b.entries.Back()
+	e := el.Value.(*lfuEntry)
+	m.unlink(el)
+	delete(m.items, e.Key)
+	if m.onEvict != nil {
+		m.onEvict(e.Key, e.Value)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func entryKeys(entries []Entry) []string {
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+// TestLRUEviction проверяет вытеснение самого давно использованного ключа
+func TestLRUEviction(t *testing.T) {
+	var evicted []string
+	m := NewLRUStringIntMap(3, func(key string, value int) {
+		evicted = append(evicted, fmt.Sprintf("%s=%d", key, value))
+	})
+
+	m.Add("a", 1)
+	m.Add("b", 2)
+	m.Add("c", 3)
+	m.Get("a")    // a становится самым недавним
+	m.Exists("b") // Exists не влияет на порядок
+	m.Add("d", 4) // вытесняет b
+
+	if !reflect.DeepEqual(evicted, []string{"b=2"}) {
+		t.Errorf("Expected b to be evicted, got %v", evicted)
+	}
+	if m.Exists("b") || m.Len() != 3 {
+		t.Errorf("Unexpected contents after eviction: %v", m.Copy())
+	}
+
+	m.Add("c", 30) // перезапись обновляет значение и порядок
+	expected := []string{"c", "d", "a"}
+	if got := entryKeys(m.Entries()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected recency order %v, got %v", expected, got)
+	}
+	if v, _ := m.Get("c"); v != 30 {
+		t.Errorf("Expected c=30, got %d", v)
+	}
+
+	m.Remove("a")
+	m.Add("e", 5)
+	if len(evicted) != 1 {
+		t.Errorf("No eviction expected after Remove freed space, got %v", evicted)
+	}
+}
+
+// TestLFUEviction проверяет вытеснение самого редко используемого ключа
+func TestLFUEviction(t *testing.T) {
+	var evicted []string
+	m := NewLFUStringIntMap(3, func(key string, value int) {
+		evicted = append(evicted, key)
+	})
+
+	m.Add("a", 1)
+	m.Add("b", 2)
+	m.Add("c", 3)
+	m.Get("a")
+	m.Get("a")
+	m.Get("b")
+	m.Add("d", 4) // c использовался реже всех
+
+	if !reflect.DeepEqual(evicted, []string{"c"}) {
+		t.Fatalf("Expected c to be evicted, got %v", evicted)
+	}
+
+	m.Add("e", 5) // d и e с частотой 1, d давнее
+	if !reflect.DeepEqual(evicted, []string{"c", "d"}) {
+		t.Fatalf("Expected d to be evicted, got %v", evicted)
+	}
+
+	expected := []string{"a", "b", "e"}
+	if got := entryKeys(m.Entries()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected frequency order %v, got %v", expected, got)
+	}
+}
+
+func TestLFURemove(t *testing.T) {
+	m := NewLFUStringIntMap(2, nil)
+	m.Add("a", 1)
+	m.Get("a")
+	m.Add("b", 2)
+	m.Remove("b")
+	m.Remove("missing")
+
+	m.Add("c", 3)
+	m.Add("d", 4) // вытесняет c, a используется чаще
+
+	if !m.Exists("a") || m.Exists("c") || !m.Exists("d") {
+		t.Errorf("Unexpected contents: %v", m.Copy())
+	}
+	if !reflect.DeepEqual(m.Copy(), map[string]int{"a": 1, "d": 4}) {
+		t.Errorf("Unexpected copy: %v", m.Copy())
+	}
+}
+
+func TestBoundedMapsImplementInterface(t *testing.T) {
+	var _ StringIntMapInterface = NewLRUStringIntMap(1, nil)
+	var _ StringIntMapInterface = NewLFUStringIntMap(1, nil)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// AtomicStringIntMapInterface дополняет StringIntMapInterface операциями
+// чтения-изменения-записи, которые выполняются атомарно
+type AtomicStringIntMapInterface interface {
+	StringIntMapInterface
+	// Increment прибавляет delta к значению (отсутствующий ключ считается нулём) и возвращает результат
+	Increment(key string, delta int) int
+	// CompareAndSwap записывает new, только если ключ есть и его значение равно old
+	CompareAndSwap(key string, old, new int) bool
+	// GetOrAdd возвращает существующее значение и true либо добавляет value и возвращает его и false
+	GetOrAdd(key string, value int) (int, bool)
+	// Update вызывает fn с текущим значением под блокировкой. Если fn вернула false, ключ удаляется.
+	// Возвращает итоговое значение и признак наличия ключа
+	Update(key string, fn func(old int, ok bool) (int, bool)) (int, bool)
+	// LoadAndDelete удаляет ключ и возвращает его прежнее значение
+	LoadAndDelete(key string) (int, bool)
+}
+
+// SyncStringIntMap — StringIntMap, защищённый RWMutex. Чтения выполняются параллельно
+type SyncStringIntMap struct {
+	mtx  sync.RWMutex
+	data map[string]int
+}
+
+func NewSyncStringIntMap() *SyncStringIntMap {
+	return &SyncStringIntMap{
+		data: make(map[string]int),
+	}
+}
+
+func (m *SyncStringIntMap) Add(key string, value int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.data[key] = value
+}
+
+func (m *SyncStringIntMap) Remove(key string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.data, key)
+}
+
+func (m *SyncStringIntMap) Copy() map[string]int {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	newMap := make(map[string]int, len(m.data))
+	for k, v := range m.data {
+		newMap[k] = v
+	}
+	return newMap
+}
+
+func (m *SyncStringIntMap) Exists(key string) bool {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	_, ok := m.data[key]
+	return ok
+}
+
+func (m *SyncStringIntMap) Get(key string) (int, bool) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	val, ok := m.data[key]
+	return val, ok
+}
+
+func (m *SyncStringIntMap) Increment(key string, delta int) int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.data[key] += delta
+	return m.data[key]
+}
+
+func (m *SyncStringIntMap) CompareAndSwap(key string, old, new int) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if val, ok := m.data[key]; !ok || val != old {
+		return false
+	}
+	m.data[key] = new
+	return true
+}
+
+func (m *SyncStringIntMap) GetOrAdd(key string, value int) (int, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if val, ok := m.data[key]; ok {
+		return val, true
+	}
+	m.data[key] = value
+	return value, false
+}
+
+func (m *SyncStringIntMap) Update(key string, fn func(old int, ok bool) (int, bool)) (int, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	old, ok := m.data[key]
+	val, keep := fn(old, ok)
+	if !keep {
+		delete(m.data, key)
+		return 0, false
+	}
+	m.data[key] = val
+	return val, true
+}
+
+func (m *SyncStringIntMap) LoadAndDelete(key string) (int, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	val, ok := m.data[key]
+	delete(m.data, key)
+	return val, ok
+}
+
+const defaultShardCount = 32
+
+// ShardedStringIntMap делит ключи между несколькими SyncStringIntMap по хешу,
+// чтобы запись в один шард не блокировала остальные
+type ShardedStringIntMap struct {
+	seed   maphash.Seed
+	shards []*SyncStringIntMap
+}
+
+// NewShardedStringIntMap создаёт мапу с shardCount шардами, при shardCount <= 0 используется значение по умолчанию
+func NewShardedStringIntMap(shardCount int) *ShardedStringIntMap {
+	if shardCount <= 0 {
+		shardCount = defaultShardCount
+	}
+	m := &ShardedStringIntMap{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*SyncStringIntMap, shardCount),
+	}
+	for i := range m.shards {
+		m.shards[i] = NewSyncStringIntMap()
+	}
+	return m
+}
+
+func (m *ShardedStringIntMap) shard(key string) *SyncStringIntMap {
+	return m.shards[maphash.String(m.seed, key)%uint64(len(m.shards))]
+}
+
+func (m *ShardedStringIntMap) Add(key string, value int) {
+	m.shard(key).Add(key, value)
+}
+
+func (m *ShardedStringIntMap) Remove(key string) {
+	m.shard(key).Remove(key)
+}
+
+// Copy блокирует шарды по очереди, поэтому снимок согласован внутри шарда, но не между ними
+func (m *ShardedStringIntMap) Copy() map[string]int {
+	newMap := make(map[string]int)
+	for _, s := range m.shards {
+		s.mtx.RLock()
+		for k, v := range s.data {
+			newMap[k] = v
+		}
+		s.mtx.RUnlock()
+	}
+	return newMap
+}
+
+func (m *ShardedStringIntMap) Exists(key string) bool {
+	return m.shard(key).Exists(key)
+}
+
+func (m *ShardedStringIntMap) Get(key string) (int, bool) {
+	return m.shard(key).Get(key)
+}
+
+func (m *ShardedStringIntMap) Increment(key string, delta int) int {
+	return m.shard(key).Increment(key, delta)
+}
+
+func (m *ShardedStringIntMap) CompareAndSwap(key string, old, new int) bool {
+	return m.shard(key).CompareAndSwap(key, old, new)
+}
+
+func (m *ShardedStringIntMap) GetOrAdd(key string, value int) (int, bool) {
+	return m.shard(key).GetOrAdd(key, value)
+}
+
+func (m *ShardedStringIntMap) Update(key string, fn func(old int, ok bool) (int, bool)) (int, bool) {
+	return m.shard(key).Update(key, fn)
+}
+
+func (m *ShardedStringIntMap) LoadAndDelete(key string) (int, bool) {
+	return m.shard(key).LoadAndDelete(key)
+}
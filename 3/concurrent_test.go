@@ -0,0 +1,221 @@
+package main
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func concurrentMaps() map[string]func() StringIntMapInterface {
+	return map[string]func() StringIntMapInterface{
+		"sync":    func() StringIntMapInterface { return NewSyncStringIntMap() },
+		"sharded": func() StringIntMapInterface { return NewShardedStringIntMap(8) },
+	}
+}
+
+// TestConcurrentMapsBasic проверяет базовый контракт интерфейса для потокобезопасных мап
+func TestConcurrentMapsBasic(t *testing.T) {
+	for name, newMap := range concurrentMaps() {
+		t.Run(name, func(t *testing.T) {
+			m := newMap()
+			m.Add("first", 11)
+			m.Add("second", 22)
+			m.Add("first", 33)
+
+			if v, ok := m.Get("first"); !ok || v != 33 {
+				t.Errorf("Expected first=33, got %d (exists: %v)", v, ok)
+			}
+
+			m.Remove("second")
+			if m.Exists("second") {
+				t.Error("second should be removed")
+			}
+
+			copied := m.Copy()
+			copied["third"] = 3
+			if len(copied) != 2 || m.Exists("third") {
+				t.Errorf("Copy is not independent: %v", copied)
+			}
+		})
+	}
+}
+
+// TestConcurrentMapsRace запускает параллельные чтения и записи; имеет смысл с флагом -race
+func TestConcurrentMapsRace(t *testing.T) {
+	const goroutines = 8
+	const perGoroutine = 500
+
+	for name, newMap := range concurrentMaps() {
+		t.Run(name, func(t *testing.T) {
+			m := newMap()
+			var wg sync.WaitGroup
+
+			for g := range goroutines {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					for i := range perGoroutine {
+						key := strconv.Itoa(g*perGoroutine + i)
+						m.Add(key, i)
+						if i%10 == 0 {
+							m.Remove(key)
+						}
+					}
+				}()
+				go func() {
+					defer wg.Done()
+					for i := range perGoroutine {
+						m.Get(strconv.Itoa(i))
+						m.Exists(strconv.Itoa(i))
+						if i%100 == 0 {
+							m.Copy()
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			expected := goroutines * perGoroutine * 9 / 10
+			if got := len(m.Copy()); got != expected {
+				t.Errorf("Expected %d keys, got %d", expected, got)
+			}
+		})
+	}
+}
+
+func atomicMaps() map[string]func(t *testing.T) AtomicStringIntMapInterface {
+	return map[string]func(t *testing.T) AtomicStringIntMapInterface{
+		"sync":     func(*testing.T) AtomicStringIntMapInterface { return NewSyncStringIntMap() },
+		"sharded":  func(*testing.T) AtomicStringIntMapInterface { return NewShardedStringIntMap(8) },
+		"expiring": func(*testing.T) AtomicStringIntMapInterface { return NewExpiringStringIntMap(time.Hour, nil) },
+		"durable": func(t *testing.T) AtomicStringIntMapInterface {
+			m := openDurable(t, t.TempDir(), DurableOptions{Sync: SyncNever})
+			t.Cleanup(func() { m.Close() })
+			return m
+		},
+	}
+}
+
+// TestAtomicOperations проверяет семантику операций чтения-изменения-записи
+func TestAtomicOperations(t *testing.T) {
+	for name, newMap := range atomicMaps() {
+		t.Run(name, func(t *testing.T) {
+			m := newMap(t)
+
+			if v := m.Increment("hits", 5); v != 5 {
+				t.Errorf("Increment on missing key: expected 5, got %d", v)
+			}
+			if v := m.Increment("hits", -2); v != 3 {
+				t.Errorf("Increment: expected 3, got %d", v)
+			}
+
+			if m.CompareAndSwap("hits", 10, 20) {
+				t.Error("CompareAndSwap succeeded with wrong old value")
+			}
+			if m.CompareAndSwap("missing", 0, 1) {
+				t.Error("CompareAndSwap succeeded on missing key")
+			}
+			if !m.CompareAndSwap("hits", 3, 30) {
+				t.Error("CompareAndSwap failed with correct old value")
+			}
+
+			if v, loaded := m.GetOrAdd("hits", 1); !loaded || v != 30 {
+				t.Errorf("GetOrAdd existing: expected 30, true; got %d, %v", v, loaded)
+			}
+			if v, loaded := m.GetOrAdd("new", 7); loaded || v != 7 {
+				t.Errorf("GetOrAdd missing: expected 7, false; got %d, %v", v, loaded)
+			}
+
+			v, ok := m.Update("hits", func(old int, ok bool) (int, bool) { return old * 2, true })
+			if !ok || v != 60 {
+				t.Errorf("Update: expected 60, got %d (ok: %v)", v, ok)
+			}
+			if _, ok := m.Update("new", func(int, bool) (int, bool) { return 0, false }); ok || m.Exists("new") {
+				t.Error("Update returning false should delete the key")
+			}
+
+			if v, ok := m.LoadAndDelete("hits"); !ok || v != 60 || m.Exists("hits") {
+				t.Errorf("LoadAndDelete: expected 60, got %d (ok: %v)", v, ok)
+			}
+			if _, ok := m.LoadAndDelete("hits"); ok {
+				t.Error("LoadAndDelete on missing key returned ok")
+			}
+		})
+	}
+}
+
+// TestAtomicIncrementConcurrent проверяет, что параллельные инкременты не теряются
+func TestAtomicIncrementConcurrent(t *testing.T) {
+	const goroutines = 8
+	const perGoroutine = 1000
+
+	for name, newMap := range atomicMaps() {
+		t.Run(name, func(t *testing.T) {
+			m := newMap(t)
+			var wg sync.WaitGroup
+			for range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range perGoroutine {
+						m.Increment("counter", 1)
+						for {
+							old, _ := m.Get("cas")
+							if m.CompareAndSwap("cas", old, old+1) {
+								break
+							}
+							m.GetOrAdd("cas", 0)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			for _, key := range []string{"counter", "cas"} {
+				if v, _ := m.Get(key); v != goroutines*perGoroutine {
+					t.Errorf("%s: expected %d, got %d", key, goroutines*perGoroutine, v)
+				}
+			}
+		})
+	}
+}
+
+// benchmarkContention — 90% чтений, 10% записей по общему набору ключей
+func benchmarkContention(b *testing.B, get func(string) bool, add func(string, int)) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		add(keys[i], i)
+	}
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			if i%10 == 0 {
+				add(key, i)
+			} else {
+				get(key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkContentionSyncStringIntMap(b *testing.B) {
+	m := NewSyncStringIntMap()
+	benchmarkContention(b, m.Exists, m.Add)
+}
+
+func BenchmarkContentionShardedStringIntMap(b *testing.B) {
+	m := NewShardedStringIntMap(0)
+	benchmarkContention(b, m.Exists, m.Add)
+}
+
+func BenchmarkContentionSyncMap(b *testing.B) {
+	var m sync.Map
+	benchmarkContention(b,
+		func(key string) bool { _, ok := m.Load(key); return ok },
+		func(key string, value int) { m.Store(key, value) },
+	)
+}
@@ -0,0 +1,485 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SyncPolicy определяет, когда журнал сбрасывается на диск через fsync
+type SyncPolicy int
+
+const (
+	SyncAlways   SyncPolicy = iota // после каждой записи
+	SyncInterval                   // в фоне раз в DurableOptions.SyncInterval
+	SyncNever                      // решает операционная система
+)
+
+const (
+	walFileName      = "wal.log"
+	snapshotFileName = "snapshot.db"
+
+	opAdd    byte = 1
+	opRemove byte = 2
+
+	recordHeaderSize = 8 // длина payload и crc32, по 4 байта
+	maxRecordSize    = 1 << 20
+	// maxKeySize — самый длинный ключ, запись которого уместится в maxRecordSize
+	maxKeySize = maxRecordSize - 1 - binary.MaxVarintLen64
+)
+
+var (
+	ErrClosed          = errors.New("durable map: closed")
+	ErrCorruptSnapshot = errors.New("durable map: corrupt snapshot")
+	ErrCorruptWAL      = errors.New("durable map: corrupt WAL")
+	ErrKeyTooLarge     = errors.New("durable map: key too large")
+)
+
+type DurableOptions struct {
+	Sync         SyncPolicy
+	SyncInterval time.Duration
+	// SnapshotEvery — после стольких записей журнал сворачивается в снимок, 0 — только вручную через Compact
+	SnapshotEvery int
+}
+
+// DurableStringIntMap — StringIntMap, каждое изменение которого дописывается в
+// журнал с контрольной суммой. При открытии состояние восстанавливается из
+// последнего снимка и журнала, оборванная последняя запись отбрасывается, а
+// любое другое повреждение журнала даёт ErrCorruptWAL.
+// Add и Remove не возвращают ошибок из-за интерфейса, первая ошибка ввода-вывода
+// сохраняется и доступна через Err, после неё изменения не принимаются. Если
+// запись или fsync не удались, журнал обрезается до прежней длины, чтобы
+// отвергнутое изменение не вернулось при открытии. Ключ длиннее maxKeySize
+// отклоняет только эту операцию, причина доступна через Rejected
+type DurableStringIntMap struct {
+	mtx        sync.Mutex
+	dir        string
+	opts       DurableOptions
+	mem        *StringIntMap
+	wal        *os.File
+	walSize    int64
+	records    int
+	err        error
+	rejected   error
+	stop, done chan struct{}
+}
+
+// OpenDurableStringIntMap открывает или создаёт хранилище в каталоге dir
+func OpenDurableStringIntMap(dir string, opts DurableOptions) (*DurableStringIntMap, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	m := &DurableStringIntMap{
+		dir:  dir,
+		opts: opts,
+		mem:  NewStringIntMap(),
+	}
+	if err := m.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := m.replayWAL(); err != nil {
+		return nil, err
+	}
+
+	if opts.Sync == SyncInterval {
+		if opts.SyncInterval <= 0 {
+			opts.SyncInterval = time.Second
+		}
+		m.stop = make(chan struct{})
+		m.done = make(chan struct{})
+		go m.syncLoop(opts.SyncInterval)
+	}
+	return m, nil
+}
+
+func (m *DurableStringIntMap) Add(key string, value int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.apply(key, value, true)
+}
+
+func (m *DurableStringIntMap) Remove(key string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.mem.Exists(key) {
+		m.apply(key, 0, false)
+	}
+}
+
+func (m *DurableStringIntMap) Increment(key string, delta int) int {
+	val, _ := m.Update(key, func(old int, _ bool) (int, bool) { return old + delta, true })
+	return val
+}
+
+func (m *DurableStringIntMap) CompareAndSwap(key string, old, new int) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if val, ok := m.mem.Get(key); !ok || val != old {
+		return false
+	}
+	return m.apply(key, new, true)
+}
+
+func (m *DurableStringIntMap) GetOrAdd(key string, value int) (int, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if val, ok := m.mem.Get(key); ok {
+		return val, true
+	}
+	m.apply(key, value, true)
+	return value, false
+}
+
+// Update вызывает fn под блокировкой и записывает результат в журнал. Если
+// запись не удалась, мапа не меняется и возвращается прежнее состояние ключа
+func (m *DurableStringIntMap) Update(key string, fn func(old int, ok bool) (int, bool)) (int, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	old, ok := m.mem.Get(key)
+	val, keep := fn(old, ok)
+	if !keep && !ok {
+		return 0, false
+	}
+	if !m.apply(key, val, keep) {
+		return old, ok
+	}
+	if !keep {
+		return 0, false
+	}
+	return val, true
+}
+
+func (m *DurableStringIntMap) LoadAndDelete(key string) (int, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	val, ok := m.mem.Get(key)
+	if !ok || !m.apply(key, 0, false) {
+		return 0, false
+	}
+	return val, true
+}
+
+// apply записывает в журнал и применяет к памяти добавление (keep) или удаление ключа. Вызывается под mtx
+func (m *DurableStringIntMap) apply(key string, value int, keep bool) bool {
+	op := opAdd
+	if !keep {
+		op = opRemove
+	}
+	if !m.write(op, key, value) {
+		return false
+	}
+	if keep {
+		m.mem.Add(key, value)
+	} else {
+		m.mem.Remove(key)
+	}
+	m.maybeCompact()
+	return true
+}
+
+func (m *DurableStringIntMap) Copy() map[string]int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.mem.Copy()
+}
+
+func (m *DurableStringIntMap) Exists(key string) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.mem.Exists(key)
+}
+
+func (m *DurableStringIntMap) Get(key string) (int, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.mem.Get(key)
+}
+
+// Err возвращает первую ошибку записи в журнал
+func (m *DurableStringIntMap) Err() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.err
+}
+
+// Rejected возвращает причину последней отклонённой операции или nil
+func (m *DurableStringIntMap) Rejected() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.rejected
+}
+
+// Sync сбрасывает журнал на диск независимо от политики
+func (m *DurableStringIntMap) Sync() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.wal == nil {
+		return ErrClosed
+	}
+	return m.wal.Sync()
+}
+
+// Compact записывает текущее состояние в снимок и очищает журнал
+func (m *DurableStringIntMap) Compact() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.wal == nil {
+		return ErrClosed
+	}
+	return m.compact()
+}
+
+func (m *DurableStringIntMap) Close() error {
+	if m.stop != nil {
+		close(m.stop)
+		<-m.done
+		m.stop = nil
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.wal == nil {
+		return ErrClosed
+	}
+	err := errors.Join(m.wal.Sync(), m.wal.Close())
+	m.wal = nil
+	if m.err == nil {
+		m.err = ErrClosed
+	}
+	return err
+}
+
+func (m *DurableStringIntMap) write(op byte, key string, value int) bool {
+	if m.err != nil {
+		return false
+	}
+	if len(key) > maxKeySize {
+		m.rejected = fmt.Errorf("%w: %d bytes, limit %d", ErrKeyTooLarge, len(key), maxKeySize)
+		return false
+	}
+	rec := encodeRecord(op, key, value)
+	_, err := m.wal.Write(rec)
+	if err == nil && m.opts.Sync == SyncAlways {
+		err = m.wal.Sync()
+	}
+	if err != nil {
+		m.err = errors.Join(err, m.rollback())
+		return false
+	}
+	m.walSize += int64(len(rec))
+	m.records++
+	return true
+}
+
+// rollback обрезает журнал до последней принятой записи. Если fsync не удался,
+// часть записи могла попасть на диск, и без обрезки изменение, которого нет в
+// памяти, вернулось бы при следующем открытии
+func (m *DurableStringIntMap) rollback() error {
+	if err := m.wal.Truncate(m.walSize); err != nil {
+		return err
+	}
+	_, err := m.wal.Seek(m.walSize, io.SeekStart)
+	return err
+}
+
+func (m *DurableStringIntMap) maybeCompact() {
+	if m.opts.SnapshotEvery > 0 && m.records >= m.opts.SnapshotEvery {
+		if err := m.compact(); err != nil {
+			m.err = err
+		}
+	}
+}
+
+// compact пишет снимок во временный файл и атомарно переименовывает его.
+// Если процесс упадёт до очистки журнала, повторное применение журнала к
+// снимку даст то же состояние, так как записи хранят итоговые значения
+func (m *DurableStringIntMap) compact() error {
+	tmp := filepath.Join(m.dir, snapshotFileName+".tmp")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for k, v := range m.mem.data {
+		if _, err := w.Write(encodeRecord(opAdd, k, v)); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := errors.Join(w.Flush(), f.Sync(), f.Close()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(m.dir, snapshotFileName)); err != nil {
+		return err
+	}
+	if err := syncDir(m.dir); err != nil {
+		return err
+	}
+
+	if err := m.wal.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := m.wal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	m.walSize, m.records = 0, 0
+	return m.wal.Sync()
+}
+
+func (m *DurableStringIntMap) loadSnapshot() error {
+	f, err := os.Open(filepath.Join(m.dir, snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		op, key, value, _, err := readRecord(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil || op != opAdd {
+			// Снимок пишется атомарно, поэтому любое повреждение — ошибка, а не оборванная запись
+			return fmt.Errorf("%w: %v", ErrCorruptSnapshot, err)
+		}
+		m.mem.Add(key, value)
+	}
+}
+
+// replayWAL применяет журнал и обрезает оборванную последнюю запись. Обрывом
+// считается только нехватка байт заголовка или payload в конце файла; неверная
+// длина или контрольная сумма — повреждение: тогда журнал не трогается и
+// возвращается ErrCorruptWAL
+func (m *DurableStringIntMap) replayWAL() error {
+	f, err := os.OpenFile(filepath.Join(m.dir, walFileName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		op, key, value, n, err := readRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, errTornRecord) {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("%w: record at offset %d: %v", ErrCorruptWAL, offset, err)
+		}
+		switch op {
+		case opAdd:
+			m.mem.Add(key, value)
+		case opRemove:
+			m.mem.Remove(key)
+		}
+		offset += int64(n)
+		m.records++
+	}
+
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+	m.wal, m.walSize = f, offset
+	return nil
+}
+
+func (m *DurableStringIntMap) syncLoop(interval time.Duration) {
+	defer close(m.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.mtx.Lock()
+			if m.wal != nil && m.err == nil {
+				if err := m.wal.Sync(); err != nil {
+					m.err = err
+				}
+			}
+			m.mtx.Unlock()
+		}
+	}
+}
+
+// encodeRecord кодирует запись как [длина][crc32][op][value varint][key]
+func encodeRecord(op byte, key string, value int) []byte {
+	payload := make([]byte, 0, 1+binary.MaxVarintLen64+len(key))
+	payload = append(payload, op)
+	payload = binary.AppendVarint(payload, int64(value))
+	payload = append(payload, key...)
+
+	rec := make([]byte, recordHeaderSize, recordHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(rec[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(rec[4:8], crc32.ChecksumIEEE(payload))
+	return append(rec, payload...)
+}
+
+// errTornRecord — файл кончился посреди записи
+var errTornRecord = errors.New("torn record")
+
+// readRecord читает одну запись и возвращает её размер в байтах.
+// io.EOF — конец файла ровно на границе записи, errTornRecord — конец файла
+// посреди записи, любая другая ошибка — повреждение
+func readRecord(r io.Reader) (op byte, key string, value int, n int, err error) {
+	var header [recordHeaderSize]byte
+	if _, err = io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = fmt.Errorf("%w: short header", errTornRecord)
+		}
+		return
+	}
+	// encodeRecord не пишет таких длин, значит это повреждение, а не обрыв
+	size := binary.LittleEndian.Uint32(header[0:4])
+	if size < 2 || size > maxRecordSize {
+		err = fmt.Errorf("invalid record size %d", size)
+		return
+	}
+	payload := make([]byte, size)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = fmt.Errorf("%w: short payload", errTornRecord)
+		}
+		return
+	}
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(header[4:8]) {
+		err = errors.New("checksum mismatch")
+		return
+	}
+
+	v, vn := binary.Varint(payload[1:])
+	if vn <= 0 {
+		err = errors.New("invalid value")
+		return
+	}
+	return payload[0], string(payload[1+vn:]), int(v), recordHeaderSize + int(size), nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func openDurable(t *testing.T, dir string, opts DurableOptions) *DurableStringIntMap {
+	t.Helper()
+	m, err := OpenDurableStringIntMap(dir, opts)
+	if err != nil {
+		t.Fatalf("OpenDurableStringIntMap: %v", err)
+	}
+	return m
+}
+
+// TestDurableRecovery проверяет восстановление состояния после переоткрытия для всех политик fsync
+func TestDurableRecovery(t *testing.T) {
+	policies := map[string]DurableOptions{
+		"always":   {Sync: SyncAlways},
+		"interval": {Sync: SyncInterval, SyncInterval: time.Millisecond},
+		"never":    {Sync: SyncNever},
+	}
+
+	for name, opts := range policies {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			m := openDurable(t, dir, opts)
+			m.Add("first", 11)
+			m.Add("second", 22)
+			m.Add("first", 33)
+			m.Remove("second")
+			m.Add("third", -5)
+			if err := m.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			m = openDurable(t, dir, opts)
+			defer m.Close()
+			expected := map[string]int{"first": 33, "third": -5}
+			if got := m.Copy(); !reflect.DeepEqual(got, expected) {
+				t.Errorf("Expected %v after reopen, got %v", expected, got)
+			}
+		})
+	}
+}
+
+// TestDurableTornRecord проверяет отбрасывание оборванной последней записи
+func TestDurableTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	m := openDurable(t, dir, DurableOptions{})
+	m.Add("a", 1)
+	m.Add("b", 2)
+	m.Close()
+
+	walPath := filepath.Join(dir, walFileName)
+	info, _ := os.Stat(walPath)
+	goodSize := info.Size()
+
+	// Дописываем половину записи, как при падении посреди write
+	rec := encodeRecord(opAdd, "c", 3)
+	f, _ := os.OpenFile(walPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.Write(rec[:len(rec)/2])
+	f.Close()
+
+	m = openDurable(t, dir, DurableOptions{})
+	if got := m.Copy(); !reflect.DeepEqual(got, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("Unexpected state after torn record: %v", got)
+	}
+	info, _ = os.Stat(walPath)
+	if info.Size() != goodSize {
+		t.Errorf("Expected WAL truncated to %d bytes, got %d", goodSize, info.Size())
+	}
+
+	// Новые записи продолжаются после обрезки
+	m.Add("d", 4)
+	m.Close()
+	m = openDurable(t, dir, DurableOptions{})
+	defer m.Close()
+	if v, ok := m.Get("d"); !ok || v != 4 {
+		t.Errorf("Expected d=4 after reopen, got %d (exists: %v)", v, ok)
+	}
+}
+
+// TestDurableChecksumMismatch проверяет, что полная запись с неверной суммой
+// считается повреждением даже в конце журнала
+func TestDurableChecksumMismatch(t *testing.T) {
+	dir := t.TempDir()
+	m := openDurable(t, dir, DurableOptions{})
+	m.Add("a", 1)
+	m.Add("b", 2)
+	m.Close()
+
+	walPath := filepath.Join(dir, walFileName)
+	data, _ := os.ReadFile(walPath)
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(walPath, data, 0o644)
+
+	if _, err := OpenDurableStringIntMap(dir, DurableOptions{}); !errors.Is(err, ErrCorruptWAL) {
+		t.Fatalf("Expected ErrCorruptWAL, got %v", err)
+	}
+	if after, _ := os.ReadFile(walPath); len(after) != len(data) {
+		t.Errorf("Corrupt WAL must not be truncated: %d -> %d bytes", len(data), len(after))
+	}
+}
+
+// TestDurableCorruptMiddle проверяет, что повреждение перед целыми записями не обрезает журнал
+func TestDurableCorruptMiddle(t *testing.T) {
+	dir := t.TempDir()
+	m := openDurable(t, dir, DurableOptions{})
+	m.Add("a", 1)
+	m.Add("b", 2)
+	m.Add("c", 3)
+	m.Close()
+
+	walPath := filepath.Join(dir, walFileName)
+	data, _ := os.ReadFile(walPath)
+	first := len(encodeRecord(opAdd, "a", 1))
+	data[first+recordHeaderSize] ^= 0xff // payload второй записи
+	os.WriteFile(walPath, data, 0o644)
+
+	if _, err := OpenDurableStringIntMap(dir, DurableOptions{}); !errors.Is(err, ErrCorruptWAL) {
+		t.Fatalf("Expected ErrCorruptWAL, got %v", err)
+	}
+	if after, _ := os.ReadFile(walPath); len(after) != len(data) {
+		t.Errorf("Corrupt WAL must not be truncated: %d -> %d bytes", len(data), len(after))
+	}
+}
+
+// TestDurableCorruptLength проверяет, что испорченная длина средней записи —
+// повреждение, даже если она указывает за конец файла
+func TestDurableCorruptLength(t *testing.T) {
+	for _, size := range []uint32{0, 1, maxRecordSize + 1, 0xFFFFFF} {
+		dir := t.TempDir()
+		m := openDurable(t, dir, DurableOptions{})
+		for i, k := range []string{"a", "b", "c", "d", "e"} {
+			m.Add(k, i)
+		}
+		m.Close()
+
+		walPath := filepath.Join(dir, walFileName)
+		data, _ := os.ReadFile(walPath)
+		second := len(encodeRecord(opAdd, "a", 0))
+		binary.LittleEndian.PutUint32(data[second:], size)
+		os.WriteFile(walPath, data, 0o644)
+
+		if _, err := OpenDurableStringIntMap(dir, DurableOptions{}); !errors.Is(err, ErrCorruptWAL) {
+			t.Errorf("Size %d: expected ErrCorruptWAL, got %v", size, err)
+		}
+		if after, _ := os.ReadFile(walPath); len(after) != len(data) {
+			t.Errorf("Size %d: corrupt WAL must not be truncated: %d -> %d bytes", size, len(data), len(after))
+		}
+	}
+}
+
+// TestDurableKeyTooLarge проверяет, что слишком длинный ключ отклоняется, а не теряет последующие записи
+func TestDurableKeyTooLarge(t *testing.T) {
+	dir := t.TempDir()
+	m := openDurable(t, dir, DurableOptions{})
+	m.Add("small", 1)
+	m.Add(string(make([]byte, maxKeySize+1)), 2)
+	if err := m.Rejected(); !errors.Is(err, ErrKeyTooLarge) {
+		t.Fatalf("Expected ErrKeyTooLarge, got %v", err)
+	}
+	// Отклоняется только сама операция, следующие записи принимаются
+	m.Add("next", 5)
+	if err := m.Err(); err != nil {
+		t.Fatalf("Rejected key must not disable the map: %v", err)
+	}
+	m.Close()
+
+	// Ключ предельной длины записывается и читается обратно, в том числе из снимка
+	m = openDurable(t, dir, DurableOptions{})
+	big := string(make([]byte, maxKeySize))
+	m.Add(big, 3)
+	m.Add("after", 4)
+	if err := m.Err(); err != nil {
+		t.Fatal(err)
+	}
+	m.Close()
+
+	m = openDurable(t, dir, DurableOptions{})
+	if got := len(m.Copy()); got != 4 {
+		t.Errorf("Expected 4 keys from WAL, got %d", got)
+	}
+	if err := m.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	m.Close()
+
+	m = openDurable(t, dir, DurableOptions{})
+	defer m.Close()
+	if v, _ := m.Get(big); v != 3 || len(m.Copy()) != 4 {
+		t.Errorf("Expected 4 keys from snapshot, got %d", len(m.Copy()))
+	}
+}
+
+// TestDurableCompaction проверяет автоматический снимок и очистку журнала
+func TestDurableCompaction(t *testing.T) {
+	dir := t.TempDir()
+	opts := DurableOptions{SnapshotEvery: 3}
+	m := openDurable(t, dir, opts)
+	for i := range 10 {
+		m.Add("counter", i)
+	}
+	m.Add("other", 1)
+	m.Remove("other")
+
+	info, err := os.Stat(filepath.Join(dir, snapshotFileName))
+	if err != nil || info.Size() == 0 {
+		t.Fatalf("Expected snapshot file, got %v", err)
+	}
+	if m.records >= 3 {
+		t.Errorf("Expected WAL to be compacted, got %d records", m.records)
+	}
+	m.Close()
+
+	m = openDurable(t, dir, opts)
+	defer m.Close()
+	if got := m.Copy(); !reflect.DeepEqual(got, map[string]int{"counter": 9}) {
+		t.Errorf("Unexpected state after compaction: %v", got)
+	}
+}
+
+func TestDurableCorruptSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	m := openDurable(t, dir, DurableOptions{})
+	m.Add("a", 1)
+	if err := m.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	m.Close()
+
+	snapPath := filepath.Join(dir, snapshotFileName)
+	data, _ := os.ReadFile(snapPath)
+	os.WriteFile(snapPath, data[:len(data)-1], 0o644)
+
+	if _, err := OpenDurableStringIntMap(dir, DurableOptions{}); !errors.Is(err, ErrCorruptSnapshot) {
+		t.Errorf("Expected ErrCorruptSnapshot, got %v", err)
+	}
+}
+
+func TestDurableClosed(t *testing.T) {
+	m := openDurable(t, t.TempDir(), DurableOptions{})
+	m.Close()
+
+	m.Add("a", 1)
+	if m.Exists("a") {
+		t.Error("Add after Close should be rejected")
+	}
+	if !errors.Is(m.Err(), ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", m.Err())
+	}
+	if err := m.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed on second Close, got %v", err)
+	}
+}
+
+// TestDurableAtomicOperations проверяет, что атомарные операции попадают в журнал
+func TestDurableAtomicOperations(t *testing.T) {
+	dir := t.TempDir()
+	m := openDurable(t, dir, DurableOptions{})
+	m.Increment("hits", 5)
+	m.GetOrAdd("new", 7)
+	m.CompareAndSwap("hits", 5, 6)
+	m.Update("gone", func(int, bool) (int, bool) { return 1, true })
+	m.LoadAndDelete("gone")
+	m.Close()
+
+	m = openDurable(t, dir, DurableOptions{})
+	defer m.Close()
+	if got := m.Copy(); !reflect.DeepEqual(got, map[string]int{"hits": 6, "new": 7}) {
+		t.Errorf("Unexpected state after reopen: %v", got)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+type expiringValue struct {
+	value     int
+	expiresAt time.Time // нулевое время — ключ не истекает
+}
+
+// ExpiringStringIntMap — StringIntMap с временем жизни ключей. Истёкшие ключи
+// не видны через Get, Exists и Copy, удаляются лениво при обращении и
+// фоновым сборщиком, если он запущен через StartJanitor. Атомарные операции
+// сохраняют срок жизни существующего ключа, а новый ключ получают с defaultTTL,
+// так что счётчик через Increment живёт окно от первого увеличения
+type ExpiringStringIntMap struct {
+	mtx        sync.Mutex
+	data       map[string]expiringValue
+	defaultTTL time.Duration
+	now        func() time.Time
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewExpiringStringIntMap создаёт мапу, в которой Add использует defaultTTL
+// (0 — без истечения). now позволяет подменить часы в тестах, nil — time.Now
+func NewExpiringStringIntMap(defaultTTL time.Duration, now func() time.Time) *ExpiringStringIntMap {
+	if now == nil {
+		now = time.Now
+	}
+	return &ExpiringStringIntMap{
+		data:       make(map[string]expiringValue),
+		defaultTTL: defaultTTL,
+		now:        now,
+	}
+}
+
+func (m *ExpiringStringIntMap) Add(key string, value int) {
+	m.AddWithTTL(key, value, m.defaultTTL)
+}
+
+// AddWithTTL добавляет ключ, который истечёт через ttl; ttl <= 0 — без истечения
+func (m *ExpiringStringIntMap) AddWithTTL(key string, value int, ttl time.Duration) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	v := expiringValue{value: value}
+	if ttl > 0 {
+		v.expiresAt = m.now().Add(ttl)
+	}
+	m.data[key] = v
+}
+
+func (m *ExpiringStringIntMap) Remove(key string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.data, key)
+}
+
+func (m *ExpiringStringIntMap) Copy() map[string]int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	now := m.now()
+	newMap := make(map[string]int, len(m.data))
+	for k, v := range m.data {
+		if v.expired(now) {
+			delete(m.data, k)
+			continue
+		}
+		newMap[k] = v.value
+	}
+	return newMap
+}
+
+func (m *ExpiringStringIntMap) Exists(key string) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+func (m *ExpiringStringIntMap) Get(key string) (int, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	v, ok := m.live(key)
+	return v.value, ok
+}
+
+// live возвращает неистёкшее значение ключа, удаляя истёкшее. Вызывается под mtx
+func (m *ExpiringStringIntMap) live(key string) (expiringValue, bool) {
+	v, ok := m.data[key]
+	if !ok {
+		return expiringValue{}, false
+	}
+	if v.expired(m.now()) {
+		delete(m.data, key)
+		return expiringValue{}, false
+	}
+	return v, true
+}
+
+func (m *ExpiringStringIntMap) Increment(key string, delta int) int {
+	val, _ := m.Update(key, func(old int, _ bool) (int, bool) { return old + delta, true })
+	return val
+}
+
+func (m *ExpiringStringIntMap) CompareAndSwap(key string, old, new int) bool {
+	swapped := false
+	m.Update(key, func(val int, ok bool) (int, bool) {
+		if !ok {
+			return 0, false
+		}
+		if val == old {
+			val, swapped = new, true
+		}
+		return val, true
+	})
+	return swapped
+}
+
+func (m *ExpiringStringIntMap) GetOrAdd(key string, value int) (int, bool) {
+	loaded := false
+	val, _ := m.Update(key, func(old int, ok bool) (int, bool) {
+		if ok {
+			loaded = true
+			return old, true
+		}
+		return value, true
+	})
+	return val, loaded
+}
+
+func (m *ExpiringStringIntMap) Update(key string, fn func(old int, ok bool) (int, bool)) (int, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	v, ok := m.live(key)
+	val, keep := fn(v.value, ok)
+	if !keep {
+		delete(m.data, key)
+		return 0, false
+	}
+	if !ok && m.defaultTTL > 0 {
+		v.expiresAt = m.now().Add(m.defaultTTL)
+	}
+	v.value = val
+	m.data[key] = v
+	return val, true
+}
+
+func (m *ExpiringStringIntMap) LoadAndDelete(key string) (int, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	v, ok := m.live(key)
+	delete(m.data, key)
+	return v.value, ok
+}
+
+// TTL возвращает оставшееся время жизни ключа; 0 и true — ключ без истечения
+func (m *ExpiringStringIntMap) TTL(key string) (time.Duration, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	v, ok := m.live(key)
+	if !ok {
+		return 0, false
+	}
+	if v.expiresAt.IsZero() {
+		return 0, true
+	}
+	return v.expiresAt.Sub(m.now()), true
+}
+
+// DeleteExpired удаляет все истёкшие ключи и возвращает их количество
+func (m *ExpiringStringIntMap) DeleteExpired() int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	now := m.now()
+	removed := 0
+	for k, v := range m.data {
+		if v.expired(now) {
+			delete(m.data, k)
+			removed++
+		}
+	}
+	return removed
+}
+
+// StartJanitor запускает фоновую очистку истёкших ключей раз в interval.
+// Повторный вызов без Stop ничего не делает
+func (m *ExpiringStringIntMap) StartJanitor(interval time.Duration) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go func(stop, done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				m.DeleteExpired()
+			}
+		}
+	}(m.stop, m.done)
+}
+
+// Stop останавливает сборщик и дожидается завершения его горутины
+func (m *ExpiringStringIntMap) Stop() {
+	m.mtx.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.mtx.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (v expiringValue) expired(now time.Time) bool {
+	return !v.expiresAt.IsZero() && !now.Before(v.expiresAt)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock — управляемые часы для тестов без sleep
+type fakeClock struct {
+	mtx sync.Mutex
+	t   time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// TestExpiringMapTTL проверяет, что истёкшие ключи не видны через Get, Exists и Copy
+func TestExpiringMapTTL(t *testing.T) {
+	clock := newFakeClock()
+	m := NewExpiringStringIntMap(0, clock.Now)
+
+	m.Add("forever", 1)
+	m.AddWithTTL("short", 2, time.Second)
+	m.AddWithTTL("long", 3, time.Minute)
+
+	if v, ok := m.Get("short"); !ok || v != 2 {
+		t.Fatalf("Expected short=2 before expiry, got %d (exists: %v)", v, ok)
+	}
+
+	clock.Advance(time.Second)
+
+	if m.Exists("short") {
+		t.Error("short should be expired")
+	}
+	if _, ok := m.Get("short"); ok {
+		t.Error("Get returned expired key")
+	}
+	copied := m.Copy()
+	if len(copied) != 2 || copied["forever"] != 1 || copied["long"] != 3 {
+		t.Errorf("Unexpected copy after expiry: %v", copied)
+	}
+
+	if ttl, ok := m.TTL("long"); !ok || ttl != 59*time.Second {
+		t.Errorf("Expected TTL 59s, got %v (exists: %v)", ttl, ok)
+	}
+	if ttl, ok := m.TTL("forever"); !ok || ttl != 0 {
+		t.Errorf("Expected no TTL for forever, got %v (exists: %v)", ttl, ok)
+	}
+}
+
+func TestExpiringMapDefaultTTLAndOverwrite(t *testing.T) {
+	clock := newFakeClock()
+	m := NewExpiringStringIntMap(10*time.Second, clock.Now)
+
+	m.Add("key", 1)
+	clock.Advance(8 * time.Second)
+	// Перезапись продлевает время жизни
+	m.Add("key", 2)
+	clock.Advance(8 * time.Second)
+
+	if v, ok := m.Get("key"); !ok || v != 2 {
+		t.Errorf("Expected key=2, got %d (exists: %v)", v, ok)
+	}
+
+	clock.Advance(2 * time.Second)
+	if m.Exists("key") {
+		t.Error("key should be expired after default TTL")
+	}
+}
+
+// TestExpiringMapLazyRemoval проверяет, что истёкший ключ удаляется из хранилища при обращении
+// TestExpiringMapIncrementWindow проверяет счётчик с фиксированным окном:
+// Increment не продлевает срок, истёкший ключ начинается заново
+func TestExpiringMapIncrementWindow(t *testing.T) {
+	clock := newFakeClock()
+	m := NewExpiringStringIntMap(time.Minute, clock.Now)
+
+	m.Increment("requests", 1)
+	clock.Advance(30 * time.Second)
+	if v := m.Increment("requests", 1); v != 2 {
+		t.Errorf("Expected 2 within the window, got %d", v)
+	}
+	if ttl, _ := m.TTL("requests"); ttl != 30*time.Second {
+		t.Errorf("Increment must keep the original TTL, got %v", ttl)
+	}
+
+	clock.Advance(30 * time.Second)
+	if m.CompareAndSwap("requests", 2, 3) {
+		t.Error("CompareAndSwap succeeded on expired key")
+	}
+	if v := m.Increment("requests", 1); v != 1 {
+		t.Errorf("Expected a new window after expiry, got %d", v)
+	}
+	if ttl, _ := m.TTL("requests"); ttl != time.Minute {
+		t.Errorf("New key must get the default TTL, got %v", ttl)
+	}
+}
+
+func TestExpiringMapLazyRemoval(t *testing.T) {
+	clock := newFakeClock()
+	m := NewExpiringStringIntMap(time.Second, clock.Now)
+	m.Add("a", 1)
+	m.Add("b", 2)
+	clock.Advance(time.Second)
+
+	m.Get("a")
+	if _, ok := m.data["a"]; ok {
+		t.Error("Expired key was not removed on access")
+	}
+	if removed := m.DeleteExpired(); removed != 1 {
+		t.Errorf("Expected DeleteExpired to remove 1 key, got %d", removed)
+	}
+	if len(m.data) != 0 {
+		t.Errorf("Expected empty storage, got %v", m.data)
+	}
+}
+
+// TestExpiringMapJanitor проверяет фоновую очистку и корректную остановку
+func TestExpiringMapJanitor(t *testing.T) {
+	clock := newFakeClock()
+	m := NewExpiringStringIntMap(time.Second, clock.Now)
+	m.Add("key", 1)
+	clock.Advance(time.Second)
+
+	m.StartJanitor(time.Millisecond)
+	m.StartJanitor(time.Millisecond)
+
+	deadline := time.After(time.Second)
+	for {
+		m.mtx.Lock()
+		size := len(m.data)
+		m.mtx.Unlock()
+		if size == 0 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("janitor did not remove expired key")
+		default:
+			runtime.Gosched()
+		}
+	}
+
+	m.Stop()
+	m.Stop()
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+	maxBodySize      = 10 << 20
+)
+
+type keyValue struct {
+	Key   string `json:"key"`
+	Value int    `json:"value"`
+}
+
+type keyPage struct {
+	Items      []keyValue `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// HTTPHandler отдаёт мапу по HTTP/JSON:
+//
+//	GET    /keys/{key}  — значение ключа с ETag
+//	PUT    /keys/{key}  — запись {"value": n}, поддерживает If-Match и If-None-Match: *
+//	DELETE /keys/{key}  — удаление, поддерживает If-Match
+//	GET    /keys        — список по возрастанию ключей, параметры prefix, limit и cursor
+//	POST   /keys        — массовая загрузка из JSON-объекта {"key": value, ...}
+//
+// ETag вычисляется по значению, поэтому совпадает у одинаковых значений.
+// Проверка условия и запись выполняются под одной блокировкой, так что
+// условные запросы атомарны относительно других запросов к обработчику
+type HTTPHandler struct {
+	mtx sync.Mutex
+	m   StringIntMapInterface
+	mux *http.ServeMux
+}
+
+func NewHTTPHandler(m StringIntMapInterface) *HTTPHandler {
+	h := &HTTPHandler{m: m, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /keys/{key}", h.getKey)
+	h.mux.HandleFunc("PUT /keys/{key}", h.putKey)
+	h.mux.HandleFunc("DELETE /keys/{key}", h.deleteKey)
+	h.mux.HandleFunc("GET /keys", h.listKeys)
+	h.mux.HandleFunc("POST /keys", h.importKeys)
+	return h
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *HTTPHandler) getKey(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	h.mtx.Lock()
+	val, ok := h.m.Get(key)
+	h.mtx.Unlock()
+
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "key not found")
+		return
+	}
+	etag := valueETag(val)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, keyValue{Key: key, Value: val})
+}
+
+func (h *HTTPHandler) putKey(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Value *int `json:"value"`
+	}
+	if err := decodeJSON(w, r, &body); err != nil || body.Value == nil {
+		writeJSONError(w, http.StatusBadRequest, `body must be {"value": <integer>}`)
+		return
+	}
+
+	key := r.PathValue("key")
+	h.mtx.Lock()
+	old, exists := h.m.Get(key)
+	if !h.checkPreconditions(w, r, old, exists) {
+		h.mtx.Unlock()
+		return
+	}
+	h.m.Add(key, *body.Value)
+	h.mtx.Unlock()
+
+	w.Header().Set("ETag", valueETag(*body.Value))
+	status := http.StatusOK
+	if !exists {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, keyValue{Key: key, Value: *body.Value})
+}
+
+func (h *HTTPHandler) deleteKey(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	old, exists := h.m.Get(key)
+	if !exists {
+		writeJSONError(w, http.StatusNotFound, "key not found")
+		return
+	}
+	if !h.checkPreconditions(w, r, old, exists) {
+		return
+	}
+	h.m.Remove(key)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) listKeys(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix, cursor := q.Get("prefix"), q.Get("cursor")
+	limit := defaultPageLimit
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || n > maxPageLimit {
+			writeJSONError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxPageLimit))
+			return
+		}
+		limit = n
+	}
+
+	h.mtx.Lock()
+	data := h.m.Copy()
+	h.mtx.Unlock()
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		if strings.HasPrefix(k, prefix) && (cursor == "" || k > cursor) {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	page := keyPage{Items: []keyValue{}}
+	for _, k := range keys[:min(limit, len(keys))] {
+		page.Items = append(page.Items, keyValue{Key: k, Value: data[k]})
+	}
+	if len(keys) > limit {
+		page.NextCursor = keys[limit-1]
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (h *HTTPHandler) importKeys(w http.ResponseWriter, r *http.Request) {
+	var body map[string]int
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "body must be a JSON object of integer values: "+err.Error())
+		return
+	}
+
+	h.mtx.Lock()
+	for k, v := range body {
+		h.m.Add(k, v)
+	}
+	h.mtx.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]int{"imported": len(body)})
+}
+
+// checkPreconditions проверяет If-Match и If-None-Match и при несовпадении
+// сам отвечает 412. Вызывается под блокировкой
+func (h *HTTPHandler) checkPreconditions(w http.ResponseWriter, r *http.Request, old int, exists bool) bool {
+	etag := ""
+	if exists {
+		etag = valueETag(old)
+	}
+	if m := r.Header.Get("If-Match"); m != "" && (!exists || !etagMatches(m, etag)) {
+		writeJSONError(w, http.StatusPreconditionFailed, "If-Match precondition failed")
+		return false
+	}
+	if m := r.Header.Get("If-None-Match"); m != "" && exists && etagMatches(m, etag) {
+		writeJSONError(w, http.StatusPreconditionFailed, "If-None-Match precondition failed")
+		return false
+	}
+	return true
+}
+
+// valueETag — само значение в кавычках: разные значения не могут получить один ETag
+func valueETag(value int) string {
+	return `"` + strconv.Itoa(value) + `"`
+}
+
+// etagMatches проверяет список ETag из заголовка, включая "*". Слабые ETag сравниваются как сильные
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func doRequest(t *testing.T, h http.Handler, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestHTTPKeyLifecycle проверяет создание, чтение, изменение и удаление ключа
+func TestHTTPKeyLifecycle(t *testing.T) {
+	m := NewStringIntMap()
+	h := NewHTTPHandler(m)
+
+	rec := doRequest(t, h, "PUT", "/keys/counter", `{"value": 5}`, nil)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("PUT new key: expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = doRequest(t, h, "GET", "/keys/counter", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET: expected 200, got %d", rec.Code)
+	}
+	var kv keyValue
+	json.NewDecoder(rec.Body).Decode(&kv)
+	if kv != (keyValue{Key: "counter", Value: 5}) {
+		t.Errorf("Unexpected body %+v", kv)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag != `"5"` {
+		t.Fatalf("Expected ETag %q, got %q", `"5"`, etag)
+	}
+
+	rec = doRequest(t, h, "GET", "/keys/counter", "", map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Conditional GET: expected 304, got %d", rec.Code)
+	}
+
+	rec = doRequest(t, h, "PUT", "/keys/counter", `{"value": 6}`, nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("PUT existing key: expected 200 with new ETag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	rec = doRequest(t, h, "DELETE", "/keys/counter", "", nil)
+	if rec.Code != http.StatusNoContent || m.Exists("counter") {
+		t.Errorf("DELETE: expected 204 and removed key, got %d", rec.Code)
+	}
+	rec = doRequest(t, h, "DELETE", "/keys/counter", "", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("DELETE missing: expected 404, got %d", rec.Code)
+	}
+	rec = doRequest(t, h, "GET", "/keys/counter", "", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET missing: expected 404, got %d", rec.Code)
+	}
+}
+
+// TestHTTPOptimisticConcurrency проверяет If-Match и If-None-Match при записи
+func TestHTTPOptimisticConcurrency(t *testing.T) {
+	m := NewStringIntMap()
+	m.Add("key", 1)
+	h := NewHTTPHandler(m)
+
+	etag := doRequest(t, h, "GET", "/keys/key", "", nil).Header().Get("ETag")
+
+	// Кто-то другой успел изменить значение
+	doRequest(t, h, "PUT", "/keys/key", `{"value": 2}`, nil)
+
+	rec := doRequest(t, h, "PUT", "/keys/key", `{"value": 3}`, map[string]string{"If-Match": etag})
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("Stale If-Match: expected 412, got %d", rec.Code)
+	}
+	if v, _ := m.Get("key"); v != 2 {
+		t.Errorf("Stale write was applied: %d", v)
+	}
+
+	fresh := doRequest(t, h, "GET", "/keys/key", "", nil).Header().Get("ETag")
+	rec = doRequest(t, h, "PUT", "/keys/key", `{"value": 3}`, map[string]string{"If-Match": fresh})
+	if rec.Code != http.StatusOK {
+		t.Errorf("Fresh If-Match: expected 200, got %d", rec.Code)
+	}
+
+	rec = doRequest(t, h, "DELETE", "/keys/key", "", map[string]string{"If-Match": fresh})
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("DELETE with stale If-Match: expected 412, got %d", rec.Code)
+	}
+
+	rec = doRequest(t, h, "PUT", "/keys/key", `{"value": 4}`, map[string]string{"If-None-Match": "*"})
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("Create-only PUT on existing key: expected 412, got %d", rec.Code)
+	}
+	rec = doRequest(t, h, "PUT", "/keys/new", `{"value": 4}`, map[string]string{"If-None-Match": "*"})
+	if rec.Code != http.StatusCreated {
+		t.Errorf("Create-only PUT on new key: expected 201, got %d", rec.Code)
+	}
+}
+
+func TestHTTPBadRequests(t *testing.T) {
+	h := NewHTTPHandler(NewStringIntMap())
+
+	bodies := []string{``, `{"value": "x"}`, `{"value": 1.5}`, `{}`, `{"value": 1, "extra": 2}`, `{"value": 1} {}`}
+	for _, body := range bodies {
+		rec := doRequest(t, h, "PUT", "/keys/a", body, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("PUT body %q: expected 400, got %d", body, rec.Code)
+		}
+	}
+
+	if rec := doRequest(t, h, "GET", "/keys?limit=0", "", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("Invalid limit: expected 400, got %d", rec.Code)
+	}
+	if rec := doRequest(t, h, "POST", "/keys", `{"a": "x"}`, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("Invalid import: expected 400, got %d", rec.Code)
+	}
+}
+
+// TestHTTPListAndImport проверяет массовую загрузку и постраничный список с фильтром по префиксу
+func TestHTTPListAndImport(t *testing.T) {
+	h := NewHTTPHandler(NewSyncStringIntMap())
+
+	rec := doRequest(t, h, "POST", "/keys", `{"user:1": 1, "user:2": 2, "user:3": 3, "admin": 0}`, nil)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"imported":4`) {
+		t.Fatalf("Import: unexpected response %d %s", rec.Code, rec.Body)
+	}
+
+	var pages [][]keyValue
+	cursor := ""
+	for {
+		rec := doRequest(t, h, "GET", "/keys?prefix=user:&limit=2&cursor="+cursor, "", nil)
+		var page keyPage
+		if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, page.Items)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	expected := [][]keyValue{
+		{{Key: "user:1", Value: 1}, {Key: "user:2", Value: 2}},
+		{{Key: "user:3", Value: 3}},
+	}
+	if !reflect.DeepEqual(pages, expected) {
+		t.Errorf("Expected pages %v, got %v", expected, pages)
+	}
+
+	rec = doRequest(t, h, "GET", "/keys?prefix=nobody", "", nil)
+	if !strings.Contains(rec.Body.String(), `"items":[]`) {
+		t.Errorf("Expected empty items array, got %s", rec.Body)
+	}
+}
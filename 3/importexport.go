@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type Format int
+
+const (
+	FormatCSV       Format = iota // заголовок key,value и строки с парами
+	FormatJSON                    // один объект {"key": value, ...}
+	FormatJSONLines               // по объекту {"key": ..., "value": ...} в строке
+)
+
+// MergePolicy определяет, что делать при импорте ключа, который уже есть в мапе
+type MergePolicy int
+
+const (
+	MergeOverwrite MergePolicy = iota
+	MergeSkip
+	MergeSum
+)
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+// ImportError указывает строку входных данных, на которой импорт остановился
+type ImportError struct {
+	Line int
+	Err  error
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// Export записывает содержимое мапы в w в порядке возрастания ключей
+func Export(w io.Writer, m StringIntMapInterface, format Format) error {
+	data := m.Copy()
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	bw := bufio.NewWriter(w)
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(bw)
+		cw.Write([]string{"key", "value"})
+		for _, k := range keys {
+			cw.Write([]string{k, strconv.Itoa(data[k])})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	case FormatJSON:
+		bw.WriteString("{")
+		for i, k := range keys {
+			if i > 0 {
+				bw.WriteString(",")
+			}
+			name, _ := json.Marshal(k)
+			fmt.Fprintf(bw, "\n  %s: %d", name, data[k])
+		}
+		bw.WriteString("\n}\n")
+	case FormatJSONLines:
+		enc := json.NewEncoder(bw)
+		for _, k := range keys {
+			if err := enc.Encode(keyValue{Key: k, Value: data[k]}); err != nil {
+				return err
+			}
+		}
+	default:
+		return ErrUnknownFormat
+	}
+	return bw.Flush()
+}
+
+// Import читает пары из r и добавляет их в m согласно policy. Данные
+// применяются по мере чтения: при ошибке строки до неё уже добавлены.
+// Возвращает количество обработанных пар; ошибки формата — *ImportError
+func Import(r io.Reader, m StringIntMapInterface, format Format, policy MergePolicy) (int, error) {
+	count := 0
+	apply := func(key string, value int) {
+		count++
+		old, exists := m.Get(key)
+		switch {
+		case !exists || policy == MergeOverwrite:
+			m.Add(key, value)
+		case policy == MergeSum:
+			m.Add(key, old+value)
+		}
+	}
+
+	var err error
+	switch format {
+	case FormatCSV:
+		err = importCSV(r, apply)
+	case FormatJSON:
+		err = importJSON(r, apply)
+	case FormatJSONLines:
+		err = importJSONLines(r, apply)
+	default:
+		err = ErrUnknownFormat
+	}
+	return count, err
+}
+
+func importCSV(r io.Reader, apply func(string, int)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	first := true
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if errors.As(err, &perr) {
+				return &ImportError{Line: perr.Line, Err: perr.Err}
+			}
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		if len(record) != 2 {
+			return &ImportError{Line: line, Err: fmt.Errorf("expected 2 fields, got %d", len(record))}
+		}
+		// Заголовок необязателен и распознаётся только в первой строке
+		if first && strings.EqualFold(record[0], "key") && strings.EqualFold(record[1], "value") {
+			first = false
+			continue
+		}
+		first = false
+
+		value, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil {
+			return &ImportError{Line: line, Err: fmt.Errorf("value %q is not an integer", record[1])}
+		}
+		apply(record[0], value)
+	}
+}
+
+func importJSONLines(r io.Reader, apply func(string, int)) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxBodySize)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.UseNumber()
+		dec.DisallowUnknownFields()
+		var row struct {
+			Key   *string      `json:"key"`
+			Value *json.Number `json:"value"`
+		}
+		if err := dec.Decode(&row); err != nil {
+			return &ImportError{Line: line, Err: err}
+		}
+		if row.Key == nil || row.Value == nil {
+			return &ImportError{Line: line, Err: errors.New(`row must have "key" and "value"`)}
+		}
+		value, err := strconv.Atoi(row.Value.String())
+		if err != nil {
+			return &ImportError{Line: line, Err: fmt.Errorf("value %s is not an integer", row.Value)}
+		}
+		apply(*row.Key, value)
+	}
+	return sc.Err()
+}
+
+func importJSON(r io.Reader, apply func(string, int)) error {
+	return importJSONObject(&lineTracker{r: r}, apply)
+}
+
+func importJSONObject(lt *lineTracker, apply func(string, int)) error {
+	dec := json.NewDecoder(lt)
+	dec.UseNumber()
+	fail := func(err error) error {
+		return &ImportError{Line: lt.line(dec.InputOffset()), Err: err}
+	}
+
+	if tok, err := dec.Token(); err != nil {
+		return fail(err)
+	} else if tok != json.Delim('{') {
+		return fail(errors.New("expected JSON object"))
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fail(err)
+		}
+		key := tok.(string)
+
+		tok, err = dec.Token()
+		if err != nil {
+			return fail(err)
+		}
+		num, ok := tok.(json.Number)
+		if !ok {
+			return fail(fmt.Errorf("value of %q is not a number", key))
+		}
+		value, err := strconv.Atoi(num.String())
+		if err != nil {
+			return fail(fmt.Errorf("value of %q is not an integer: %s", key, num))
+		}
+		apply(key, value)
+		// Отбрасываем смещения прочитанных строк, иначе они копились бы до конца импорта
+		lt.line(dec.InputOffset())
+	}
+	if _, err := dec.Token(); err != nil {
+		return fail(err)
+	}
+	return nil
+}
+
+// lineTracker запоминает смещения переводов строк, чтобы по смещению
+// декодера JSON найти номер строки. Смещения до последнего запроса line
+// отбрасываются; importJSON вызывает его после каждой пары, так что хранятся
+// только переводы строк из буфера, прочитанного декодером наперёд
+type lineTracker struct {
+	r        io.Reader
+	read     int64
+	newlines []int64
+	dropped  int
+}
+
+func (t *lineTracker) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			t.newlines = append(t.newlines, t.read+int64(i))
+		}
+	}
+	t.read += int64(n)
+	return n, err
+}
+
+// line возвращает номер строки (с единицы) для смещения off. Смещения должны не убывать
+func (t *lineTracker) line(off int64) int {
+	i, _ := slices.BinarySearch(t.newlines, off)
+	t.dropped += i
+	// Сдвигаем остаток в начало, чтобы массив не рос за счёт уже отброшенных смещений
+	t.newlines = append(t.newlines[:0], t.newlines[i:]...)
+	return t.dropped + 1
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+)
+
+// TestExportImportRoundTrip проверяет, что экспорт читается импортом без потерь во всех форматах
+func TestExportImportRoundTrip(t *testing.T) {
+	src := stringIntMapFrom(map[string]int{
+		"plain":         1,
+		"with,comma":    -2,
+		`with "quotes"`: 3,
+		"юникод":        4,
+		"":              0,
+	})
+
+	for _, format := range []Format{FormatCSV, FormatJSON, FormatJSONLines} {
+		var buf bytes.Buffer
+		if err := Export(&buf, src, format); err != nil {
+			t.Fatalf("Export format %d: %v", format, err)
+		}
+		dst := NewStringIntMap()
+		n, err := Import(&buf, dst, format, MergeOverwrite)
+		if err != nil {
+			t.Fatalf("Import format %d: %v", format, err)
+		}
+		if n != 5 || !maps.Equal(dst.Copy(), src.Copy()) {
+			t.Errorf("Format %d: expected %v, got %v (%d rows)", format, src.Copy(), dst.Copy(), n)
+		}
+	}
+}
+
+func TestExportFormats(t *testing.T) {
+	m := stringIntMapFrom(map[string]int{"b": 2, "a": 1})
+
+	expected := map[Format]string{
+		FormatCSV:       "key,value\na,1\nb,2\n",
+		FormatJSON:      "{\n  \"a\": 1,\n  \"b\": 2\n}\n",
+		FormatJSONLines: "{\"key\":\"a\",\"value\":1}\n{\"key\":\"b\",\"value\":2}\n",
+	}
+	for format, want := range expected {
+		var buf bytes.Buffer
+		Export(&buf, m, format)
+		if buf.String() != want {
+			t.Errorf("Format %d: expected %q, got %q", format, want, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, NewStringIntMap(), FormatJSON); err != nil || buf.String() != "{\n}\n" {
+		t.Errorf("Empty JSON export: got %q (err: %v)", buf.String(), err)
+	}
+	if err := Export(&buf, m, Format(42)); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestImportMergePolicies(t *testing.T) {
+	input := "a,10\nc,30\n"
+	expected := map[MergePolicy]map[string]int{
+		MergeOverwrite: {"a": 10, "b": 2, "c": 30},
+		MergeSkip:      {"a": 1, "b": 2, "c": 30},
+		MergeSum:       {"a": 11, "b": 2, "c": 30},
+	}
+	for policy, want := range expected {
+		m := stringIntMapFrom(map[string]int{"a": 1, "b": 2})
+		if _, err := Import(strings.NewReader(input), m, FormatCSV, policy); err != nil {
+			t.Fatal(err)
+		}
+		if !maps.Equal(m.Copy(), want) {
+			t.Errorf("Policy %d: expected %v, got %v", policy, want, m.Copy())
+		}
+	}
+}
+
+// TestImportErrors проверяет номера строк в ошибках и то, что строки до ошибки уже применены
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		input    string
+		line     int
+		imported int
+	}{
+		{"csv not integer", FormatCSV, "key,value\na,1\nb,1.5\n", 3, 1},
+		{"csv overflow", FormatCSV, "a,99999999999999999999\n", 1, 0},
+		{"csv field count", FormatCSV, "a,1\nb\n", 2, 1},
+		{"csv bad quotes", FormatCSV, "a,1\n\"b,2\n", 2, 1},
+		{"jsonl not integer", FormatJSONLines, "{\"key\":\"a\",\"value\":1}\n\n{\"key\":\"b\",\"value\":2.5}\n", 3, 1},
+		{"jsonl missing value", FormatJSONLines, "{\"key\":\"a\"}\n", 1, 0},
+		{"jsonl unknown field", FormatJSONLines, "{\"key\":\"a\",\"value\":1,\"x\":2}\n", 1, 0},
+		{"jsonl malformed", FormatJSONLines, "{\"key\":\"a\",\"value\":1}\n{\"key\":\n", 2, 1},
+		{"json not integer", FormatJSON, "{\n  \"a\": 1,\n  \"b\": 1e3\n}\n", 3, 1},
+		{"json string value", FormatJSON, "{\n  \"a\": \"x\"\n}\n", 2, 0},
+		{"json not object", FormatJSON, "[1, 2]", 1, 0},
+		{"json truncated", FormatJSON, "{\n  \"a\": 1,\n  \"b\": 2,\n", 3, 2},
+	}
+
+	for _, tt := range tests {
+		m := NewStringIntMap()
+		n, err := Import(strings.NewReader(tt.input), m, tt.format, MergeOverwrite)
+		var ierr *ImportError
+		if !errors.As(err, &ierr) {
+			t.Errorf("%s: expected *ImportError, got %v", tt.name, err)
+			continue
+		}
+		if ierr.Line != tt.line {
+			t.Errorf("%s: expected line %d, got %d (%v)", tt.name, tt.line, ierr.Line, err)
+		}
+		if n != tt.imported || len(m.Copy()) != tt.imported {
+			t.Errorf("%s: expected %d imported rows, got %d", tt.name, tt.imported, n)
+		}
+	}
+}
+
+func TestImportCSVWithoutHeader(t *testing.T) {
+	m := NewStringIntMap()
+	n, err := Import(strings.NewReader("a, 1\nkey,2\n"), m, FormatCSV, MergeOverwrite)
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 rows, got %d (err: %v)", n, err)
+	}
+	// "key" не в первой строке — обычный ключ, а не заголовок
+	if v, _ := m.Get("key"); v != 2 {
+		t.Errorf("Expected key=2, got %d", v)
+	}
+}
+
+// TestImportJSONLineTrackerBounded проверяет, что при импорте большого объекта
+// не копятся смещения всех строк
+func TestImportJSONLineTrackerBounded(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	for i := range 10000 {
+		fmt.Fprintf(&sb, "  \"k%d\": %d,\n", i, i)
+	}
+	sb.WriteString("  \"last\": 0\n}\n")
+
+	lt := &lineTracker{r: strings.NewReader(sb.String())}
+	apply := func(string, int) {
+		if len(lt.newlines) > 1000 {
+			t.Fatalf("lineTracker holds %d offsets", len(lt.newlines))
+		}
+	}
+	if err := importJSONObject(lt, apply); err != nil {
+		t.Fatal(err)
+	}
+}
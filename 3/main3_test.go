@@ -346,3 +346,37 @@ func TestCopyModifyOriginal(t *testing.T) {
 		t.Error("Original was affected: 'newkey' should not exist")
 	}
 }
+
+// TestGenericMap проверяет обобщённую мапу с нестроковыми ключами и составными значениями
+func TestGenericMap(t *testing.T) {
+	type UserID int
+	type Session struct {
+		Token  string
+		Active bool
+	}
+
+	var m MapInterface[UserID, Session] = NewMap[UserID, Session]()
+	m.Add(1, Session{Token: "abc", Active: true})
+	m.Add(2, Session{Token: "def"})
+
+	if s, ok := m.Get(1); !ok || s.Token != "abc" || !s.Active {
+		t.Errorf("Unexpected session for user 1: %+v (exists: %v)", s, ok)
+	}
+
+	m.Remove(2)
+	if m.Exists(2) {
+		t.Error("User 2 should be removed")
+	}
+
+	copied := m.Copy()
+	copied[3] = Session{Token: "ghi"}
+	if m.Exists(3) {
+		t.Error("Original was affected by modifying copy")
+	}
+}
+
+// TestStringIntMapImplementsInterface проверяет, что StringIntMap удовлетворяет интерфейсу
+func TestStringIntMapImplementsInterface(t *testing.T) {
+	var _ StringIntMapInterface = NewStringIntMap()
+	var _ MapInterface[string, int] = &StringIntMap{data: map[string]int{}}
+}
@@ -1,52 +1,86 @@
 package main
 
-import "fmt"
-
-type StringIntMapInterface interface {
-	Add(key string, value int)
-	Remove(key string)
-	Copy() map[string]int
-	Exists(key string) bool
-	Get(key string) (int, bool)
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+type MapInterface[K comparable, V any] interface {
+	Add(key K, value V)
+	Remove(key K)
+	Copy() map[K]V
+	Exists(key K) bool
+	Get(key K) (V, bool)
 }
 
-type StringIntMap struct {
-	data map[string]int
+type StringIntMapInterface = MapInterface[string, int]
+
+type Map[K comparable, V any] struct {
+	data map[K]V
 }
 
-func NewStringIntMap() *StringIntMap {
-	return &StringIntMap{
-		data: make(map[string]int),
+type StringIntMap = Map[string, int]
+
+func NewMap[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{
+		data: make(map[K]V),
 	}
 }
 
-func (m *StringIntMap) Add(key string, value int) {
+func NewStringIntMap() *StringIntMap {
+	return NewMap[string, int]()
+}
+
+func (m *Map[K, V]) Add(key K, value V) {
 	m.data[key] = value
 }
 
-func (m *StringIntMap) Remove(key string) {
+func (m *Map[K, V]) Remove(key K) {
 	delete(m.data, key)
 }
 
-func (m *StringIntMap) Copy() map[string]int {
-	var newMap = make(map[string]int)
+func (m *Map[K, V]) Copy() map[K]V {
+	var newMap = make(map[K]V)
 	for k, v := range m.data {
 		newMap[k] = v
 	}
 	return newMap
 }
 
-func (m *StringIntMap) Exists(key string) bool {
+func (m *Map[K, V]) Exists(key K) bool {
 	_, ok := m.data[key]
 	return ok
 }
 
-func (m *StringIntMap) Get(key string) (int, bool) {
+func (m *Map[K, V]) Get(key K) (V, bool) {
 	val, ok := m.data[key]
 	return val, ok
 }
 
 func main() {
+	respAddr := flag.String("resp", "", "запустить RESP-сервер на адресе, например :6380")
+	httpAddr := flag.String("http", "", "запустить HTTP API на адресе, например :8080")
+	flag.Parse()
+	if *respAddr != "" {
+		if err := serveRESP(*respAddr); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *httpAddr != "" {
+		if err := serveHTTP(*httpAddr); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	SIMap := NewStringIntMap()
 	fmt.Println("Исходная мапа:", SIMap)
 
@@ -69,3 +103,57 @@ func main() {
 	val, ok := SIMap.Get("first")
 	fmt.Println("Есть ли элемент first в мапе:", ok, "он равен:", val)
 }
+
+// serveRESP отдаёт мапу по протоколу Redis до получения SIGINT
+func serveRESP(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	srv := NewRESPServer(NewSyncStringIntMap())
+	// Serve возвращается сразу после закрытия слушателя, а Shutdown ещё ждёт
+	// открытые соединения, поэтому выходим только после его завершения
+	shutdownErr := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownErr <- srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Println("RESP-сервер слушает", ln.Addr())
+	if err := srv.Serve(ln); err != ErrServerClosed {
+		return err
+	}
+	return <-shutdownErr
+}
+
+// serveHTTP отдаёт мапу по HTTP/JSON и метрики по /metrics до получения SIGINT
+func serveHTTP(addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	m := NewInstrumentedStringIntMap(NewSyncStringIntMap())
+	mux := http.NewServeMux()
+	mux.Handle("/", NewHTTPHandler(m))
+	mux.Handle("GET /metrics", m.MetricsHandler())
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+	// ListenAndServe вернёт ErrServerClosed, не дожидаясь активных запросов
+	shutdownErr := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownErr <- srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Println("HTTP API слушает", addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return <-shutdownErr
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+)
+
+type ChangeKind int
+
+const (
+	KeyAdded ChangeKind = iota
+	KeyRemoved
+	KeyChanged
+)
+
+type KeyChange struct {
+	Key  string
+	Kind ChangeKind
+	Old  int // для KeyAdded всегда 0
+	New  int // для KeyRemoved всегда 0
+}
+
+// Patch — набор изменений, упорядоченный по ключам
+type Patch []KeyChange
+
+// Diff возвращает изменения, превращающие содержимое a в содержимое b
+func Diff(a, b StringIntMapInterface) Patch {
+	am, bm := a.Copy(), b.Copy()
+	var patch Patch
+	for k, av := range am {
+		bv, ok := bm[k]
+		switch {
+		case !ok:
+			patch = append(patch, KeyChange{Key: k, Kind: KeyRemoved, Old: av})
+		case av != bv:
+			patch = append(patch, KeyChange{Key: k, Kind: KeyChanged, Old: av, New: bv})
+		}
+	}
+	for k, bv := range bm {
+		if _, ok := am[k]; !ok {
+			patch = append(patch, KeyChange{Key: k, Kind: KeyAdded, New: bv})
+		}
+	}
+	slices.SortFunc(patch, func(x, y KeyChange) int {
+		return cmp.Compare(x.Key, y.Key)
+	})
+	return patch
+}
+
+// Apply применяет изменения к m. Старые значения не проверяются
+func Apply(m StringIntMapInterface, patch Patch) {
+	for _, c := range patch {
+		if c.Kind == KeyRemoved {
+			m.Remove(c.Key)
+		} else {
+			m.Add(c.Key, c.New)
+		}
+	}
+}
+
+// MergeConflict описывает ключ, который обе стороны изменили по-разному
+type MergeConflict struct {
+	Key                     string
+	Base, Left, Right       int
+	InBase, InLeft, InRight bool
+}
+
+// ConflictResolver возвращает итоговое значение ключа; false — ключ удаляется
+type ConflictResolver func(c MergeConflict) (int, bool)
+
+func TakeLeft(c MergeConflict) (int, bool) {
+	return c.Left, c.InLeft
+}
+
+func TakeRight(c MergeConflict) (int, bool) {
+	return c.Right, c.InRight
+}
+
+// SumDeltas складывает изменения обеих сторон относительно базы, как у счётчиков.
+// Отсутствующий ключ считается нулём
+func SumDeltas(c MergeConflict) (int, bool) {
+	return c.Left + c.Right - c.Base, true
+}
+
+// TakeMax выбирает большее из значений сторон, удаление проигрывает изменению
+func TakeMax(c MergeConflict) (int, bool) {
+	switch {
+	case !c.InLeft:
+		return c.Right, c.InRight
+	case !c.InRight:
+		return c.Left, true
+	}
+	return max(c.Left, c.Right), true
+}
+
+// Merge3 сливает две версии left и right, разошедшиеся от общей base.
+// Изменения одной стороны применяются как есть, одинаковые изменения обеих
+// сторон — один раз, а разные изменения одного ключа передаются в resolve.
+// Возвращает результат и список конфликтов в порядке ключей
+func Merge3(base, left, right StringIntMapInterface, resolve ConflictResolver) (*StringIntMap, []MergeConflict) {
+	bm, lm, rm := base.Copy(), left.Copy(), right.Copy()
+	keys := make(map[string]struct{}, len(bm))
+	for _, m := range []map[string]int{bm, lm, rm} {
+		for k := range m {
+			keys[k] = struct{}{}
+		}
+	}
+
+	result := NewStringIntMap()
+	var conflicts []MergeConflict
+	for k := range keys {
+		c := MergeConflict{Key: k}
+		c.Base, c.InBase = bm[k]
+		c.Left, c.InLeft = lm[k]
+		c.Right, c.InRight = rm[k]
+
+		leftChanged := c.InLeft != c.InBase || c.Left != c.Base
+		rightChanged := c.InRight != c.InBase || c.Right != c.Base
+		sameChange := c.InLeft == c.InRight && c.Left == c.Right
+
+		var value int
+		var keep bool
+		switch {
+		case !rightChanged || sameChange:
+			value, keep = c.Left, c.InLeft
+		case !leftChanged:
+			value, keep = c.Right, c.InRight
+		default:
+			conflicts = append(conflicts, c)
+			value, keep = resolve(c)
+		}
+		if keep {
+			result.Add(k, value)
+		}
+	}
+
+	slices.SortFunc(conflicts, func(x, y MergeConflict) int {
+		return cmp.Compare(x.Key, y.Key)
+	})
+	return result, conflicts
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func stringIntMapFrom(data map[string]int) *StringIntMap {
+	m := NewStringIntMap()
+	for k, v := range data {
+		m.Add(k, v)
+	}
+	return m
+}
+
+// TestDiffAndApply проверяет, что применение разницы к a даёт b
+func TestDiffAndApply(t *testing.T) {
+	a := stringIntMapFrom(map[string]int{"same": 1, "changed": 2, "removed": 3})
+	b := stringIntMapFrom(map[string]int{"same": 1, "changed": 20, "added": 4})
+
+	patch := Diff(a, b)
+	expected := Patch{
+		{Key: "added", Kind: KeyAdded, New: 4},
+		{Key: "changed", Kind: KeyChanged, Old: 2, New: 20},
+		{Key: "removed", Kind: KeyRemoved, Old: 3},
+	}
+	if !reflect.DeepEqual(patch, expected) {
+		t.Fatalf("Expected patch %v, got %v", expected, patch)
+	}
+
+	Apply(a, patch)
+	if !reflect.DeepEqual(a.Copy(), b.Copy()) {
+		t.Errorf("Apply(Diff(a, b)) != b: %v vs %v", a.Copy(), b.Copy())
+	}
+	if len(Diff(a, b)) != 0 {
+		t.Error("Expected empty diff between equal maps")
+	}
+}
+
+// TestMerge3 проверяет слияние независимых копий, разошедшихся от общей базы
+func TestMerge3(t *testing.T) {
+	base := stringIntMapFrom(map[string]int{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5})
+
+	left := stringIntMapFrom(base.Copy())
+	left.Add("a", 10)     // изменил только left
+	left.Add("c", 30)     // конфликт
+	left.Remove("d")      // удаление против изменения
+	left.Add("e", 50)     // одинаковое изменение
+	left.Add("left", 100) // добавил только left
+
+	right := stringIntMapFrom(base.Copy())
+	right.Remove("b") // удалил только right
+	right.Add("c", 7)
+	right.Add("d", 40)
+	right.Add("e", 50)
+
+	tests := []struct {
+		name     string
+		resolve  ConflictResolver
+		expected map[string]int
+	}{
+		{
+			name:     "take left",
+			resolve:  TakeLeft,
+			expected: map[string]int{"a": 10, "c": 30, "e": 50, "left": 100},
+		},
+		{
+			name:     "take right",
+			resolve:  TakeRight,
+			expected: map[string]int{"a": 10, "c": 7, "d": 40, "e": 50, "left": 100},
+		},
+		{
+			name:     "sum deltas",
+			resolve:  SumDeltas,
+			expected: map[string]int{"a": 10, "c": 34, "d": 36, "e": 50, "left": 100},
+		},
+		{
+			name:     "max",
+			resolve:  TakeMax,
+			expected: map[string]int{"a": 10, "c": 30, "d": 40, "e": 50, "left": 100},
+		},
+		{
+			name:     "custom",
+			resolve:  func(c MergeConflict) (int, bool) { return -1, true },
+			expected: map[string]int{"a": 10, "c": -1, "d": -1, "e": 50, "left": 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := Merge3(base, left, right, tt.resolve)
+
+			if got := merged.Copy(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+			if len(conflicts) != 2 || conflicts[0].Key != "c" || conflicts[1].Key != "d" {
+				t.Errorf("Expected conflicts on c and d, got %+v", conflicts)
+			}
+		})
+	}
+}
+
+func TestMerge3BothAddedSameKey(t *testing.T) {
+	base := NewStringIntMap()
+	left := stringIntMapFrom(map[string]int{"new": 1})
+	right := stringIntMapFrom(map[string]int{"new": 2})
+
+	merged, conflicts := Merge3(base, left, right, SumDeltas)
+	if v, _ := merged.Get("new"); v != 3 {
+		t.Errorf("Expected new=3, got %d", v)
+	}
+	if len(conflicts) != 1 || conflicts[0].InBase {
+		t.Errorf("Unexpected conflicts: %+v", conflicts)
+	}
+}
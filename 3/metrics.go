@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBuckets — верхние границы интервалов гистограммы задержек в секундах
+var latencyBuckets = []float64{1e-7, 2.5e-7, 5e-7, 1e-6, 2.5e-6, 5e-6, 1e-5, 2.5e-5, 5e-5, 1e-4, 1e-3, 1e-2}
+
+var instrumentedOps = []string{"get", "exists", "add", "remove", "copy"}
+
+type latencyHistogram struct {
+	buckets []atomic.Uint64 // последний элемент — интервал +Inf
+	sumNs   atomic.Uint64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{buckets: make([]atomic.Uint64, len(latencyBuckets)+1)}
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	sec := d.Seconds()
+	i := 0
+	for i < len(latencyBuckets) && sec > latencyBuckets[i] {
+		i++
+	}
+	h.buckets[i].Add(1)
+	h.sumNs.Add(uint64(d.Nanoseconds()))
+}
+
+// MapStats — снимок счётчиков InstrumentedStringIntMap
+type MapStats struct {
+	Gets, Hits, Misses        uint64
+	Exists                    uint64
+	Adds, Overwrites, Removes uint64
+	Copies                    uint64
+	Size                      int64
+}
+
+// HitRatio возвращает долю успешных Get, 0 — если Get не вызывался
+func (s MapStats) HitRatio() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Gets)
+}
+
+// InstrumentedStringIntMap оборачивает любую StringIntMapInterface и считает
+// обращения, попадания, промахи, перезаписи и удаления, а также задержки
+// операций. Счётчики атомарные, поэтому обёртка потокобезопасна, если
+// потокобезопасна исходная мапа. Перезаписи считаются точно при параллельной
+// записи, только если исходная мапа реализует AtomicStringIntMapInterface.
+// Размер берётся из Len исходной мапы, если он есть (Ordered, LRU, LFU, Trie,
+// Persistent). Иначе обёртка ведёт свой счётчик, который расходится с мапой,
+// если её меняют в обход обёртки, если ключи исчезают сами (истечение TTL) или
+// если мапа без атомарных операций изменяется из нескольких горутин
+type InstrumentedStringIntMap struct {
+	inner       StringIntMapInterface
+	atomicInner AtomicStringIntMapInterface // inner, если она поддерживает атомарные операции
+	lenInner    interface{ Len() int }      // inner, если она знает свой размер
+	size        atomic.Int64                // счётчик ключей, когда lenInner нет
+
+	gets, hits, misses, exists        atomic.Uint64
+	adds, overwrites, removes, copies atomic.Uint64
+	latency                           map[string]*latencyHistogram
+}
+
+func NewInstrumentedStringIntMap(inner StringIntMapInterface) *InstrumentedStringIntMap {
+	m := &InstrumentedStringIntMap{
+		inner:   inner,
+		latency: make(map[string]*latencyHistogram, len(instrumentedOps)),
+	}
+	m.atomicInner, _ = inner.(AtomicStringIntMapInterface)
+	m.lenInner, _ = inner.(interface{ Len() int })
+	if m.lenInner == nil {
+		m.size.Store(int64(len(inner.Copy())))
+	}
+	for _, op := range instrumentedOps {
+		m.latency[op] = newLatencyHistogram()
+	}
+	return m
+}
+
+func (m *InstrumentedStringIntMap) Add(key string, value int) {
+	defer m.observe("add", time.Now())
+	var existed bool
+	if m.atomicInner != nil {
+		// Проверка и запись под одной блокировкой, иначе параллельные Add
+		// одного нового ключа оба сочли бы его новым
+		m.atomicInner.Update(key, func(_ int, ok bool) (int, bool) {
+			existed = ok
+			return value, true
+		})
+	} else {
+		existed = m.inner.Exists(key)
+		m.inner.Add(key, value)
+	}
+	m.adds.Add(1)
+	if existed {
+		m.overwrites.Add(1)
+	} else {
+		m.size.Add(1)
+	}
+}
+
+func (m *InstrumentedStringIntMap) Remove(key string) {
+	defer m.observe("remove", time.Now())
+	var existed bool
+	if m.atomicInner != nil {
+		_, existed = m.atomicInner.LoadAndDelete(key)
+	} else {
+		existed = m.inner.Exists(key)
+		m.inner.Remove(key)
+	}
+	m.removes.Add(1)
+	if existed {
+		m.size.Add(-1)
+	}
+}
+
+func (m *InstrumentedStringIntMap) Copy() map[string]int {
+	defer m.observe("copy", time.Now())
+	m.copies.Add(1)
+	return m.inner.Copy()
+}
+
+func (m *InstrumentedStringIntMap) Exists(key string) bool {
+	defer m.observe("exists", time.Now())
+	m.exists.Add(1)
+	return m.inner.Exists(key)
+}
+
+func (m *InstrumentedStringIntMap) Get(key string) (int, bool) {
+	defer m.observe("get", time.Now())
+	val, ok := m.inner.Get(key)
+	m.gets.Add(1)
+	if ok {
+		m.hits.Add(1)
+	} else {
+		m.misses.Add(1)
+	}
+	return val, ok
+}
+
+func (m *InstrumentedStringIntMap) Stats() MapStats {
+	size := m.size.Load()
+	if m.lenInner != nil {
+		size = int64(m.lenInner.Len())
+	}
+	return MapStats{
+		Gets:       m.gets.Load(),
+		Hits:       m.hits.Load(),
+		Misses:     m.misses.Load(),
+		Exists:     m.exists.Load(),
+		Adds:       m.adds.Load(),
+		Overwrites: m.overwrites.Load(),
+		Removes:    m.removes.Load(),
+		Copies:     m.copies.Load(),
+		Size:       size,
+	}
+}
+
+// WritePrometheus выводит метрики в текстовом формате Prometheus
+func (m *InstrumentedStringIntMap) WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	s := m.Stats()
+
+	fmt.Fprintln(bw, "# HELP stringintmap_operations_total Number of map operations by type.")
+	fmt.Fprintln(bw, "# TYPE stringintmap_operations_total counter")
+	opCounts := map[string]uint64{"get": s.Gets, "exists": s.Exists, "add": s.Adds, "remove": s.Removes, "copy": s.Copies}
+	for _, op := range instrumentedOps {
+		fmt.Fprintf(bw, "stringintmap_operations_total{op=%q} %d\n", op, opCounts[op])
+	}
+
+	counters := []struct {
+		name, help string
+		value      uint64
+	}{
+		{"stringintmap_hits_total", "Number of Get calls that found the key.", s.Hits},
+		{"stringintmap_misses_total", "Number of Get calls that did not find the key.", s.Misses},
+		{"stringintmap_overwrites_total", "Number of Add calls that replaced an existing key.", s.Overwrites},
+	}
+	for _, c := range counters {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", c.name, c.help, c.name, c.name, c.value)
+	}
+
+	fmt.Fprintln(bw, "# HELP stringintmap_size Current number of keys.")
+	fmt.Fprintln(bw, "# TYPE stringintmap_size gauge")
+	fmt.Fprintf(bw, "stringintmap_size %d\n", s.Size)
+
+	fmt.Fprintln(bw, "# HELP stringintmap_operation_duration_seconds Latency of map operations.")
+	fmt.Fprintln(bw, "# TYPE stringintmap_operation_duration_seconds histogram")
+	for _, op := range instrumentedOps {
+		h := m.latency[op]
+		var cumulative uint64
+		for i := range h.buckets {
+			cumulative += h.buckets[i].Load()
+			le := "+Inf"
+			if i < len(latencyBuckets) {
+				le = strconv.FormatFloat(latencyBuckets[i], 'g', -1, 64)
+			}
+			fmt.Fprintf(bw, "stringintmap_operation_duration_seconds_bucket{op=%q,le=%q} %d\n", op, le, cumulative)
+		}
+		sum := float64(h.sumNs.Load()) / float64(time.Second)
+		fmt.Fprintf(bw, "stringintmap_operation_duration_seconds_sum{op=%q} %s\n", op, strconv.FormatFloat(sum, 'g', -1, 64))
+		// count совпадает с последним кумулятивным интервалом, так вывод остаётся согласованным
+		fmt.Fprintf(bw, "stringintmap_operation_duration_seconds_count{op=%q} %d\n", op, cumulative)
+	}
+	return bw.Flush()
+}
+
+// MetricsHandler отдаёт метрики для Prometheus, обычно по пути /metrics
+func (m *InstrumentedStringIntMap) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WritePrometheus(w)
+	})
+}
+
+func (m *InstrumentedStringIntMap) observe(op string, start time.Time) {
+	m.latency[op].observe(time.Since(start))
+}
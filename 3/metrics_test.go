@@ -0,0 +1,139 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestInstrumentedCounters проверяет счётчики попаданий, промахов, перезаписей и размер
+func TestInstrumentedCounters(t *testing.T) {
+	inner := NewStringIntMap()
+	inner.Add("existing", 1)
+	m := NewInstrumentedStringIntMap(inner)
+
+	m.Add("a", 1)
+	m.Add("a", 2)
+	m.Add("b", 3)
+	m.Get("a")
+	m.Get("missing")
+	m.Get("b")
+	m.Exists("a")
+	m.Remove("b")
+	m.Remove("missing")
+	m.Copy()
+
+	expected := MapStats{
+		Gets: 3, Hits: 2, Misses: 1,
+		Exists: 1,
+		Adds:   3, Overwrites: 1, Removes: 2,
+		Copies: 1,
+		Size:   2,
+	}
+	if got := m.Stats(); got != expected {
+		t.Errorf("Expected stats %+v, got %+v", expected, got)
+	}
+	if r := m.Stats().HitRatio(); r < 0.66 || r > 0.67 {
+		t.Errorf("Expected hit ratio 2/3, got %v", r)
+	}
+	if (MapStats{}).HitRatio() != 0 {
+		t.Error("Expected zero hit ratio without gets")
+	}
+}
+
+// TestInstrumentedConcurrentAdds проверяет, что параллельные Add одного нового
+// ключа не искажают размер и число перезаписей
+func TestInstrumentedConcurrentAdds(t *testing.T) {
+	const goroutines, keys = 8, 100
+	m := NewInstrumentedStringIntMap(NewShardedStringIntMap(4))
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range keys {
+				m.Add(strconv.Itoa(k), k)
+			}
+		}()
+	}
+	wg.Wait()
+
+	s := m.Stats()
+	if s.Size != keys || s.Overwrites != (goroutines-1)*keys {
+		t.Errorf("Expected size %d and %d overwrites, got %d and %d", keys, (goroutines-1)*keys, s.Size, s.Overwrites)
+	}
+	for k := range keys {
+		m.Remove(strconv.Itoa(k))
+	}
+	if s := m.Stats(); s.Size != 0 {
+		t.Errorf("Expected size 0 after removing all keys, got %d", s.Size)
+	}
+}
+
+// TestInstrumentedConcurrent проверяет счётчики при параллельной работе поверх потокобезопасной мапы
+func TestInstrumentedConcurrent(t *testing.T) {
+	m := NewInstrumentedStringIntMap(NewSyncStringIntMap())
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				m.Get("key")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s := m.Stats(); s.Gets != 800 || s.Misses != 800 {
+		t.Errorf("Expected 800 gets and misses, got %+v", s)
+	}
+}
+
+func TestPrometheusExposition(t *testing.T) {
+	m := NewInstrumentedStringIntMap(NewStringIntMap())
+	m.Add("a", 1)
+	m.Get("a")
+	m.Get("b")
+
+	rec := httptest.NewRecorder()
+	m.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %q", ct)
+	}
+	body := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE stringintmap_operations_total counter",
+		`stringintmap_operations_total{op="get"} 2`,
+		"stringintmap_hits_total 1",
+		"stringintmap_misses_total 1",
+		"stringintmap_overwrites_total 0",
+		"# TYPE stringintmap_size gauge",
+		"stringintmap_size 1",
+		"# TYPE stringintmap_operation_duration_seconds histogram",
+		`stringintmap_operation_duration_seconds_bucket{op="get",le="+Inf"} 2`,
+		`stringintmap_operation_duration_seconds_count{op="get"} 2`,
+		`stringintmap_operation_duration_seconds_count{op="remove"} 0`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Metrics output lacks line %q", line)
+		}
+	}
+}
+
+// TestInstrumentedSizeFromLen проверяет, что размер берётся из Len исходной мапы,
+// а не из счётчика обёртки
+func TestInstrumentedSizeFromLen(t *testing.T) {
+	inner := NewOrderedStringIntMap()
+	m := NewInstrumentedStringIntMap(inner)
+	m.Add("a", 1)
+	// Изменение в обход обёртки видно, так как размер читается из мапы
+	inner.Add("b", 2)
+	if s := m.Stats(); s.Size != 2 || s.Copies != 0 {
+		t.Errorf("Expected size 2 without copies, got %+v", s)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"math/rand/v2"
+	"strings"
+)
+
+const (
+	skipMaxLevel    = 32
+	skipProbability = 0.25
+)
+
+type skipLevel struct {
+	next *skipNode
+	span int // сколько узлов нулевого уровня перепрыгивает ссылка
+}
+
+type skipNode struct {
+	Entry
+	levels []skipLevel
+}
+
+// OrderedStringIntMap — StringIntMap на списке с пропусками. Ключи хранятся
+// отсортированными, поиск, вставка, удаление, Rank и Select выполняются за
+// O(log n) в среднем. Ширина ссылок хранится, чтобы Rank и Select не проходили
+// по списку целиком
+type OrderedStringIntMap struct {
+	head   *skipNode
+	level  int
+	length int
+}
+
+func NewOrderedStringIntMap() *OrderedStringIntMap {
+	return &OrderedStringIntMap{
+		head:  &skipNode{levels: make([]skipLevel, skipMaxLevel)},
+		level: 1,
+	}
+}
+
+func randomSkipLevel() int {
+	level := 1
+	for level < skipMaxLevel && rand.Float64() < skipProbability {
+		level++
+	}
+	return level
+}
+
+// findPrev заполняет update последними узлами с ключом меньше key на каждом
+// уровне, а rank — их позициями (голова имеет позицию 0)
+func (m *OrderedStringIntMap) findPrev(key string, update *[skipMaxLevel]*skipNode, rank *[skipMaxLevel]int) {
+	x := m.head
+	for i := m.level - 1; i >= 0; i-- {
+		if i == m.level-1 {
+			rank[i] = 0
+		} else {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].next != nil && x.levels[i].next.Key < key {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+}
+
+func (m *OrderedStringIntMap) Add(key string, value int) {
+	var update [skipMaxLevel]*skipNode
+	var rank [skipMaxLevel]int
+	m.findPrev(key, &update, &rank)
+
+	if next := update[0].levels[0].next; next != nil && next.Key == key {
+		next.Value = value
+		return
+	}
+
+	level := randomSkipLevel()
+	if level > m.level {
+		for i := m.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = m.head
+			update[i].levels[i].span = m.length
+		}
+		m.level = level
+	}
+
+	x := &skipNode{Entry: Entry{Key: key, Value: value}, levels: make([]skipLevel, level)}
+	for i := range level {
+		x.levels[i].next = update[i].levels[i].next
+		update[i].levels[i].next = x
+		x.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+	for i := level; i < m.level; i++ {
+		update[i].levels[i].span++
+	}
+	m.length++
+}
+
+func (m *OrderedStringIntMap) Remove(key string) {
+	var update [skipMaxLevel]*skipNode
+	var rank [skipMaxLevel]int
+	m.findPrev(key, &update, &rank)
+
+	x := update[0].levels[0].next
+	if x == nil || x.Key != key {
+		return
+	}
+	for i := range m.level {
+		if update[i].levels[i].next == x {
+			update[i].levels[i].span += x.levels[i].span - 1
+			update[i].levels[i].next = x.levels[i].next
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	for m.level > 1 && m.head.levels[m.level-1].next == nil {
+		m.level--
+	}
+	m.length--
+}
+
+func (m *OrderedStringIntMap) Copy() map[string]int {
+	newMap := make(map[string]int, m.length)
+	for x := m.head.levels[0].next; x != nil; x = x.levels[0].next {
+		newMap[x.Key] = x.Value
+	}
+	return newMap
+}
+
+func (m *OrderedStringIntMap) Exists(key string) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+func (m *OrderedStringIntMap) Get(key string) (int, bool) {
+	x := m.ceilingNode(key)
+	if x == nil || x.Key != key {
+		return 0, false
+	}
+	return x.Value, true
+}
+
+func (m *OrderedStringIntMap) Len() int {
+	return m.length
+}
+
+// Keys возвращает ключи в порядке возрастания
+func (m *OrderedStringIntMap) Keys() []string {
+	keys := make([]string, 0, m.length)
+	for x := m.head.levels[0].next; x != nil; x = x.levels[0].next {
+		keys = append(keys, x.Key)
+	}
+	return keys
+}
+
+// Entries возвращает все записи в порядке возрастания ключей
+func (m *OrderedStringIntMap) Entries() []Entry {
+	return m.collect(m.head.levels[0].next, func(string) bool { return true })
+}
+
+// Range возвращает записи с ключами из полуинтервала [from, to)
+func (m *OrderedStringIntMap) Range(from, to string) []Entry {
+	return m.collect(m.ceilingNode(from), func(key string) bool { return key < to })
+}
+
+// Prefix возвращает записи, ключи которых начинаются с prefix, в порядке возрастания
+func (m *OrderedStringIntMap) Prefix(prefix string) []Entry {
+	return m.collect(m.ceilingNode(prefix), func(key string) bool { return strings.HasPrefix(key, prefix) })
+}
+
+// Floor возвращает запись с наибольшим ключом, не превосходящим key
+func (m *OrderedStringIntMap) Floor(key string) (Entry, bool) {
+	var update [skipMaxLevel]*skipNode
+	var rank [skipMaxLevel]int
+	m.findPrev(key, &update, &rank)
+	if next := update[0].levels[0].next; next != nil && next.Key == key {
+		return next.Entry, true
+	}
+	if update[0] == m.head {
+		return Entry{}, false
+	}
+	return update[0].Entry, true
+}
+
+// Ceiling возвращает запись с наименьшим ключом, не меньшим key
+func (m *OrderedStringIntMap) Ceiling(key string) (Entry, bool) {
+	x := m.ceilingNode(key)
+	if x == nil {
+		return Entry{}, false
+	}
+	return x.Entry, true
+}
+
+func (m *OrderedStringIntMap) Min() (Entry, bool) {
+	return m.Select(0)
+}
+
+func (m *OrderedStringIntMap) Max() (Entry, bool) {
+	return m.Select(m.length - 1)
+}
+
+// Rank возвращает количество ключей, строго меньших key
+func (m *OrderedStringIntMap) Rank(key string) int {
+	var update [skipMaxLevel]*skipNode
+	var rank [skipMaxLevel]int
+	m.findPrev(key, &update, &rank)
+	return rank[0]
+}
+
+// Select возвращает запись с i-м по возрастанию ключом, нумерация с нуля
+func (m *OrderedStringIntMap) Select(i int) (Entry, bool) {
+	if i < 0 || i >= m.length {
+		return Entry{}, false
+	}
+	x := m.head
+	traversed := 0
+	for lvl := m.level - 1; lvl >= 0; lvl-- {
+		for x.levels[lvl].next != nil && traversed+x.levels[lvl].span <= i+1 {
+			traversed += x.levels[lvl].span
+			x = x.levels[lvl].next
+		}
+		if traversed == i+1 {
+			return x.Entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+func (m *OrderedStringIntMap) ceilingNode(key string) *skipNode {
+	x := m.head
+	for i := m.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && x.levels[i].next.Key < key {
+			x = x.levels[i].next
+		}
+	}
+	return x.levels[0].next
+}
+
+func (m *OrderedStringIntMap) collect(from *skipNode, keep func(string) bool) []Entry {
+	var res []Entry
+	for x := from; x != nil && keep(x.Key); x = x.levels[0].next {
+		res = append(res, x.Entry)
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func newOrderedFrom(keys ...string) *OrderedStringIntMap {
+	m := NewOrderedStringIntMap()
+	for i, k := range keys {
+		m.Add(k, i)
+	}
+	return m
+}
+
+// TestOrderedMapMatchesReference сверяет случайную последовательность операций с обычной мапой
+func TestOrderedMapMatchesReference(t *testing.T) {
+	m := NewOrderedStringIntMap()
+	ref := make(map[string]int)
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	for i := range 5000 {
+		key := fmt.Sprintf("k%03d", rnd.IntN(300))
+		if rnd.IntN(3) == 0 {
+			m.Remove(key)
+			delete(ref, key)
+		} else {
+			m.Add(key, i)
+			ref[key] = i
+		}
+	}
+
+	if !reflect.DeepEqual(m.Copy(), ref) {
+		t.Fatal("Copy differs from reference map")
+	}
+	if m.Len() != len(ref) {
+		t.Errorf("Expected length %d, got %d", len(ref), m.Len())
+	}
+
+	keys := make([]string, 0, len(ref))
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(m.Keys(), keys) {
+		t.Fatalf("Keys are not sorted: %v", m.Keys())
+	}
+
+	for i, k := range keys {
+		if r := m.Rank(k); r != i {
+			t.Errorf("Rank(%q): expected %d, got %d", k, i, r)
+		}
+		if e, ok := m.Select(i); !ok || e.Key != k || e.Value != ref[k] {
+			t.Errorf("Select(%d): expected %q, got %+v (ok: %v)", i, k, e, ok)
+		}
+	}
+}
+
+func TestOrderedMapRangeAndPrefix(t *testing.T) {
+	m := newOrderedFrom("user:1:count", "user:123:count", "user:123:last", "user:124:count", "admin", "zeta")
+
+	// ':' больше цифр, поэтому "user:1:count" идёт после "user:124:count"
+	got := entryKeys(m.Range("b", "user:2"))
+	expected := []string{"user:123:count", "user:123:last", "user:124:count", "user:1:count"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Range: expected %v, got %v", expected, got)
+	}
+
+	got = entryKeys(m.Prefix("user:123:"))
+	expected = []string{"user:123:count", "user:123:last"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Prefix: expected %v, got %v", expected, got)
+	}
+
+	if got := m.Range("x", "a"); len(got) != 0 {
+		t.Errorf("Expected empty range, got %v", got)
+	}
+}
+
+func TestOrderedMapFloorCeiling(t *testing.T) {
+	m := newOrderedFrom("b", "d", "f")
+
+	tests := []struct {
+		key     string
+		floor   string
+		ceiling string
+	}{
+		{key: "a", floor: "", ceiling: "b"},
+		{key: "b", floor: "b", ceiling: "b"},
+		{key: "c", floor: "b", ceiling: "d"},
+		{key: "g", floor: "f", ceiling: ""},
+	}
+
+	for _, tt := range tests {
+		f, ok := m.Floor(tt.key)
+		if ok != (tt.floor != "") || f.Key != tt.floor {
+			t.Errorf("Floor(%q): expected %q, got %q (ok: %v)", tt.key, tt.floor, f.Key, ok)
+		}
+		c, ok := m.Ceiling(tt.key)
+		if ok != (tt.ceiling != "") || c.Key != tt.ceiling {
+			t.Errorf("Ceiling(%q): expected %q, got %q (ok: %v)", tt.key, tt.ceiling, c.Key, ok)
+		}
+	}
+
+	if e, ok := m.Min(); !ok || e.Key != "b" {
+		t.Errorf("Min: expected b, got %+v", e)
+	}
+	if e, ok := m.Max(); !ok || e.Key != "f" {
+		t.Errorf("Max: expected f, got %+v", e)
+	}
+}
+
+func TestOrderedMapEmpty(t *testing.T) {
+	m := NewOrderedStringIntMap()
+	var _ StringIntMapInterface = m
+
+	if _, ok := m.Min(); ok {
+		t.Error("Min on empty map returned ok")
+	}
+	if _, ok := m.Floor("a"); ok {
+		t.Error("Floor on empty map returned ok")
+	}
+	if m.Rank("a") != 0 || len(m.Keys()) != 0 {
+		t.Error("Unexpected rank or keys on empty map")
+	}
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxBulkLen = 512 << 20
+	// maxInlineLen ограничивает строку заголовка или inline-команды, как в Redis
+	maxInlineLen = 64 << 10
+	// bulkChunkSize — шаг, которым читаются bulk-строки, чтобы память выделялась по мере прихода данных
+	bulkChunkSize = 64 << 10
+)
+
+var ErrServerClosed = errors.New("resp: server closed")
+
+// respArity — число аргументов для команд с фиксированной арностью
+var respArity = map[string]int{"GET": 1, "SET": 2, "INCRBY": 2, "KEYS": 1}
+
+// RESPServer отдаёт мапу по TCP, понимая подмножество протокола Redis:
+// GET, SET, DEL, EXISTS, INCRBY, KEYS и PING. Значения — только целые числа
+type RESPServer struct {
+	m AtomicStringIntMapInterface
+
+	mtx      sync.Mutex
+	ln       net.Listener
+	conns    map[net.Conn]struct{}
+	shutdown bool
+	wg       sync.WaitGroup
+}
+
+func NewRESPServer(m AtomicStringIntMapInterface) *RESPServer {
+	return &RESPServer{
+		m:     m,
+		conns: make(map[net.Conn]struct{}),
+	}
+}
+
+// Serve принимает соединения, пока не будет вызван Shutdown, после чего возвращает ErrServerClosed
+func (s *RESPServer) Serve(ln net.Listener) error {
+	s.mtx.Lock()
+	if s.shutdown {
+		s.mtx.Unlock()
+		ln.Close()
+		return ErrServerClosed
+	}
+	s.ln = ln
+	s.mtx.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mtx.Lock()
+			closed := s.shutdown
+			s.mtx.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.mtx.Lock()
+		if s.shutdown {
+			s.mtx.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mtx.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+// Shutdown перестаёт принимать соединения и даёт текущим командам
+// завершиться. Если ctx истечёт раньше, оставшиеся соединения закрываются принудительно
+func (s *RESPServer) Shutdown(ctx context.Context) error {
+	s.mtx.Lock()
+	s.shutdown = true
+	if s.ln != nil {
+		s.ln.Close()
+	}
+	// Соединения, ожидающие следующую команду, сразу получат ошибку чтения
+	for conn := range s.conns {
+		conn.SetReadDeadline(time.Now())
+	}
+	s.mtx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mtx.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mtx.Unlock()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (s *RESPServer) handle(conn net.Conn) {
+	defer func() {
+		s.mtx.Lock()
+		delete(s.conns, conn)
+		s.mtx.Unlock()
+		conn.Close()
+		s.wg.Done()
+	}()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			var perr protocolError
+			if errors.As(err, &perr) {
+				writeError(w, "ERR Protocol error: "+perr.Error())
+				w.Flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		s.execute(w, args)
+		// Ответы копятся в буфере, пока клиент присылает команды конвейером
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *RESPServer) execute(w *bufio.Writer, args []string) {
+	cmd := strings.ToUpper(args[0])
+	args = args[1:]
+
+	if n, ok := respArity[cmd]; ok && len(args) != n {
+		writeError(w, fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(cmd)))
+		return
+	}
+
+	switch cmd {
+	case "PING":
+		switch len(args) {
+		case 0:
+			w.WriteString("+PONG\r\n")
+		case 1:
+			writeBulk(w, args[0])
+		default:
+			writeError(w, "ERR wrong number of arguments for 'ping' command")
+		}
+	case "GET":
+		if val, ok := s.m.Get(args[0]); ok {
+			writeBulk(w, strconv.Itoa(val))
+		} else {
+			w.WriteString("$-1\r\n")
+		}
+	case "SET":
+		val, err := strconv.Atoi(args[1])
+		if err != nil {
+			writeError(w, "ERR value is not an integer or out of range")
+			return
+		}
+		s.m.Add(args[0], val)
+		w.WriteString("+OK\r\n")
+	case "DEL", "EXISTS":
+		if len(args) == 0 {
+			writeError(w, fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(cmd)))
+			return
+		}
+		count := 0
+		for _, key := range args {
+			if cmd == "DEL" {
+				if _, ok := s.m.LoadAndDelete(key); ok {
+					count++
+				}
+			} else if s.m.Exists(key) {
+				count++
+			}
+		}
+		writeInt(w, count)
+	case "INCRBY":
+		delta, err := strconv.Atoi(args[1])
+		if err != nil {
+			writeError(w, "ERR value is not an integer or out of range")
+			return
+		}
+		writeInt(w, s.m.Increment(args[0], delta))
+	case "KEYS":
+		var keys []string
+		for key := range s.m.Copy() {
+			if matchGlob(args[0], key) {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		fmt.Fprintf(w, "*%d\r\n", len(keys))
+		for _, key := range keys {
+			writeBulk(w, key)
+		}
+	default:
+		writeError(w, fmt.Sprintf("ERR unknown command '%s'", strings.ToLower(cmd)))
+	}
+}
+
+type protocolError string
+
+func (e protocolError) Error() string {
+	return string(e)
+}
+
+// readCommand читает команду в виде массива bulk-строк либо inline-команду,
+// как её отправляет telnet
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 0 || n > 1024*1024 {
+		return nil, protocolError("invalid multibulk length")
+	}
+	args := make([]string, 0, min(n, 1024))
+	for range n {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, protocolError(fmt.Sprintf("expected '$', got '%s'", line))
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 || size > maxBulkLen {
+			return nil, protocolError("invalid bulk length")
+		}
+		bulk, err := readBulk(r, size)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, bulk)
+	}
+	return args, nil
+}
+
+// readBulk читает size байт и завершающий CRLF. Заявленная длина не выделяется
+// сразу: буфер растёт порциями по мере прихода данных
+func readBulk(r *bufio.Reader, size int) (string, error) {
+	buf := make([]byte, 0, min(size, bulkChunkSize))
+	for len(buf) < size {
+		chunk := min(size-len(buf), bulkChunkSize)
+		buf = slices.Grow(buf, chunk)
+		n, err := io.ReadFull(r, buf[len(buf):len(buf)+chunk])
+		buf = buf[:len(buf)+n]
+		if err != nil {
+			return "", err
+		}
+	}
+	var crlf [2]byte
+	if _, err := io.ReadFull(r, crlf[:]); err != nil {
+		return "", err
+	}
+	if crlf != [2]byte{'\r', '\n'} {
+		return "", protocolError("bulk string is not terminated by CRLF")
+	}
+	return string(buf), nil
+}
+
+// readLine читает строку до '\n', но не длиннее maxInlineLen
+func readLine(r *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		part, err := r.ReadSlice('\n')
+		if len(line)+len(part) > maxInlineLen {
+			return "", protocolError("too big inline request")
+		}
+		line = append(line, part...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(line), "\r\n"), nil
+	}
+}
+
+func writeBulk(w *bufio.Writer, s string) {
+	fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s)
+}
+
+func writeInt(w *bufio.Writer, n int) {
+	fmt.Fprintf(w, ":%d\r\n", n)
+}
+
+func writeError(w *bufio.Writer, msg string) {
+	w.WriteString("-" + msg + "\r\n")
+}
+
+// matchGlob сопоставляет строку с шаблоном в стиле Redis: '*' — любая
+// последовательность, '?' — один байт, '\' экранирует следующий символ
+func matchGlob(pattern, s string) bool {
+	p, i := 0, 0
+	starP, starI := -1, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			starP, starI = p, i
+			p++
+			continue
+		case p < len(pattern) && pattern[p] == '?':
+			p++
+			i++
+			continue
+		case p < len(pattern) && pattern[p] == '\\' && p+1 < len(pattern) && pattern[p+1] == s[i]:
+			p += 2
+			i++
+			continue
+		case p < len(pattern) && pattern[p] != '\\' && pattern[p] == s[i]:
+			p++
+			i++
+			continue
+		}
+		if starP < 0 {
+			return false
+		}
+		// Откатываемся к последней '*' и даём ей поглотить ещё один байт
+		starI++
+		p, i = starP+1, starI
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
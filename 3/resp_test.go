@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"task3/respclient"
+)
+
+// startRESPServer поднимает сервер на loopback и останавливает его по завершении теста
+func startRESPServer(t *testing.T, m AtomicStringIntMapInterface) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewRESPServer(m)
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ln) }()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+		if err := <-served; !errors.Is(err, ErrServerClosed) {
+			t.Errorf("Serve returned %v, expected ErrServerClosed", err)
+		}
+	})
+	return ln.Addr().String()
+}
+
+func dialRESP(t *testing.T, addr string) *respclient.Client {
+	t.Helper()
+	c, err := respclient.Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// TestRESPCommands проверяет все поддерживаемые команды через клиент
+func TestRESPCommands(t *testing.T) {
+	m := NewSyncStringIntMap()
+	addr := startRESPServer(t, m)
+	c := dialRESP(t, addr)
+
+	if err := c.Ping(); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if err := c.Set("user:1:count", 10); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if v, ok, err := c.Get("user:1:count"); err != nil || !ok || v != 10 {
+		t.Errorf("Get: expected 10, got %d (exists: %v, err: %v)", v, ok, err)
+	}
+	if _, ok, err := c.Get("missing"); err != nil || ok {
+		t.Errorf("Get missing: expected not found, got exists=%v err=%v", ok, err)
+	}
+	if v, err := c.IncrBy("user:1:count", 5); err != nil || v != 15 {
+		t.Errorf("IncrBy: expected 15, got %d (err: %v)", v, err)
+	}
+	if v, err := c.IncrBy("user:2:count", -3); err != nil || v != -3 {
+		t.Errorf("IncrBy on new key: expected -3, got %d (err: %v)", v, err)
+	}
+	c.Set("other", 1)
+
+	keys, err := c.Keys("user:*:count")
+	if err != nil || !slices.Equal(keys, []string{"user:1:count", "user:2:count"}) {
+		t.Errorf("Keys: unexpected %v (err: %v)", keys, err)
+	}
+	if n, err := c.Exists("user:1:count", "other", "missing"); err != nil || n != 2 {
+		t.Errorf("Exists: expected 2, got %d (err: %v)", n, err)
+	}
+	if n, err := c.Del("other", "missing"); err != nil || n != 1 {
+		t.Errorf("Del: expected 1, got %d (err: %v)", n, err)
+	}
+
+	// Сервер работает с той же мапой, что и процесс
+	if v, ok := m.Get("user:1:count"); !ok || v != 15 {
+		t.Errorf("Backing map: expected 15, got %d", v)
+	}
+}
+
+func TestRESPErrors(t *testing.T) {
+	m := NewSyncStringIntMap()
+	m.Add("key", 1)
+	addr := startRESPServer(t, m)
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	tests := []struct {
+		request  string
+		expected string
+	}{
+		{request: "*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$3\r\nabc\r\n", expected: "-ERR value is not an integer or out of range"},
+		{request: "*1\r\n$3\r\nGET\r\n", expected: "-ERR wrong number of arguments for 'get' command"},
+		{request: "FLUSHALL\r\n", expected: "-ERR unknown command 'flushall'"},
+		{request: "get key\r\n", expected: "$1"},
+		{request: "*1\r\n:1\r\n", expected: "-ERR Protocol error: expected '$', got ':1'"},
+	}
+
+	for _, tt := range tests {
+		conn.Write([]byte(tt.request))
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Reading reply to %q: %v", tt.request, err)
+		}
+		if got := strings.TrimRight(line, "\r\n"); got != tt.expected {
+			t.Errorf("Request %q: expected %q, got %q", tt.request, tt.expected, got)
+		}
+		if tt.expected == "$1" {
+			r.ReadString('\n')
+		}
+	}
+
+	if _, err := r.ReadString('\n'); err == nil {
+		t.Error("Connection should be closed after protocol error")
+	}
+}
+
+// TestRESPPipelining проверяет несколько команд, отправленных одним пакетом
+func TestRESPPipelining(t *testing.T) {
+	addr := startRESPServer(t, NewShardedStringIntMap(4))
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("SET a 1\r\nINCRBY a 2\r\nPING hello\r\n"))
+	r := bufio.NewReader(conn)
+	for _, expected := range []string{"+OK", ":3", "$5", "hello"} {
+		line, _ := r.ReadString('\n')
+		if got := strings.TrimRight(line, "\r\n"); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
+
+// TestRESPConcurrentClients проверяет параллельные INCRBY с разных соединений
+func TestRESPConcurrentClients(t *testing.T) {
+	m := NewSyncStringIntMap()
+	addr := startRESPServer(t, m)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		c := dialRESP(t, addr)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if _, err := c.IncrBy("counter", 1); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if v, _ := m.Get("counter"); v != 800 {
+		t.Errorf("Expected counter 800, got %d", v)
+	}
+}
+
+// TestRESPShutdown проверяет, что Shutdown закрывает простаивающие соединения и не принимает новые
+func TestRESPShutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewRESPServer(NewSyncStringIntMap())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ln) }()
+
+	c, err := respclient.Dial(ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if err := <-served; !errors.Is(err, ErrServerClosed) {
+		t.Errorf("Expected ErrServerClosed, got %v", err)
+	}
+	if err := c.Ping(); err == nil {
+		t.Error("Expected error on idle connection after Shutdown")
+	}
+	if _, err := respclient.Dial(ln.Addr().String()); err == nil {
+		t.Error("Expected dial error after Shutdown")
+	}
+}
+
+// TestRESPLimits проверяет, что длинная строка без перевода и огромная заявленная
+// длина bulk-строки не заставляют сервер выделять память заранее
+func TestRESPLimits(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader(strings.Repeat("a", maxInlineLen+1)))
+	if _, err := readCommand(r); err == nil || !strings.Contains(err.Error(), "too big inline request") {
+		t.Errorf("Expected inline length error, got %v", err)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	r = bufio.NewReader(strings.NewReader("*1\r\n$536870912\r\nabc"))
+	if _, err := readCommand(r); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected ErrUnexpectedEOF for truncated bulk, got %v", err)
+	}
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("Truncated 512 MiB bulk allocated %d bytes", allocated)
+	}
+
+	// Bulk-строка длиннее одной порции читается целиком
+	big := strings.Repeat("x", 3*bulkChunkSize+5)
+	r = bufio.NewReader(strings.NewReader(fmt.Sprintf("*2\r\n$3\r\nGET\r\n$%d\r\n%s\r\n", len(big), big)))
+	if args, err := readCommand(r); err != nil || len(args) != 2 || args[1] != big {
+		t.Errorf("Large bulk string: unexpected result (err: %v)", err)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		match   bool
+	}{
+		{"*", "", true},
+		{"*", "anything", true},
+		{"user:*:count", "user:1:count", true},
+		{"user:*:count", "user:1:last", false},
+		{"user:?", "user:1", true},
+		{"user:?", "user:12", false},
+		{"a*b*c", "aXXbYYc", true},
+		{"a*b*c", "aXXbYY", false},
+		{`a\*`, "a*", true},
+		{`a\*`, "ab", false},
+		{"abc", "abc", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.s); got != tt.match {
+			t.Errorf("matchGlob(%q, %q) = %v; expected %v", tt.pattern, tt.s, got, tt.match)
+		}
+	}
+}
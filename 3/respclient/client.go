@@ -0,0 +1,280 @@
+// Package respclient — клиент для RESP-сервера мапы из task3. Поддерживает
+// те же команды, что и сервер: GET, SET, DEL, EXISTS, INCRBY, KEYS и PING
+package respclient
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ServerError — ответ сервера с ошибкой, например "ERR unknown command"
+type ServerError string
+
+func (e ServerError) Error() string {
+	return string(e)
+}
+
+// Ограничения ответа совпадают с ограничениями запроса на сервере, чтобы
+// испорченный или чужой сервер не заставил клиента выделить гигабайты
+const (
+	maxBulkLen    = 512 << 20
+	maxArrayLen   = 1024 * 1024
+	maxLineLen    = 64 << 10
+	maxReplyDepth = 32
+	bulkChunkSize = 64 << 10
+)
+
+var (
+	ErrUnexpectedReply = errors.New("respclient: unexpected reply type")
+	// ErrBroken возвращается всеми вызовами после ошибки посреди ответа:
+	// остаток ответа в соединении иначе приняли бы за ответ следующей команды
+	ErrBroken = errors.New("respclient: connection is broken")
+)
+
+// Client безопасен для использования из нескольких горутин: команды
+// выполняются по одной на соединении
+type Client struct {
+	mtx  sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+	err  error // причина поломки соединения
+}
+
+func Dial(addr string) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient создаёт клиента поверх уже открытого соединения
+func NewClient(conn net.Conn) *Client {
+	return &Client{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+		w:    bufio.NewWriter(conn),
+	}
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) Ping() error {
+	reply, err := c.do("PING")
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("%w: %v", ErrUnexpectedReply, reply)
+	}
+	return nil
+}
+
+// Get возвращает значение ключа; false — ключа нет
+func (c *Client) Get(key string) (int, bool, error) {
+	reply, err := c.do("GET", key)
+	if err != nil || reply == nil {
+		return 0, false, err
+	}
+	s, ok := reply.(string)
+	if !ok {
+		return 0, false, fmt.Errorf("%w: %v", ErrUnexpectedReply, reply)
+	}
+	val, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false, err
+	}
+	return val, true, nil
+}
+
+func (c *Client) Set(key string, value int) error {
+	_, err := c.do("SET", key, strconv.Itoa(value))
+	return err
+}
+
+// Del удаляет ключи и возвращает количество удалённых
+func (c *Client) Del(keys ...string) (int, error) {
+	return c.doInt(append([]string{"DEL"}, keys...)...)
+}
+
+// Exists возвращает, сколько из переданных ключей есть в мапе
+func (c *Client) Exists(keys ...string) (int, error) {
+	return c.doInt(append([]string{"EXISTS"}, keys...)...)
+}
+
+func (c *Client) IncrBy(key string, delta int) (int, error) {
+	return c.doInt("INCRBY", key, strconv.Itoa(delta))
+}
+
+func (c *Client) Keys(pattern string) ([]string, error) {
+	reply, err := c.do("KEYS", pattern)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnexpectedReply, reply)
+	}
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrUnexpectedReply, item)
+		}
+		keys = append(keys, s)
+	}
+	return keys, nil
+}
+
+func (c *Client) doInt(args ...string) (int, error) {
+	reply, err := c.do(args...)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int)
+	if !ok {
+		return 0, fmt.Errorf("%w: %v", ErrUnexpectedReply, reply)
+	}
+	return n, nil
+}
+
+// do отправляет команду массивом bulk-строк и читает ответ
+func (c *Client) do(args ...string) (any, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := c.w.Flush(); err != nil {
+		c.err = fmt.Errorf("%w: %w", ErrBroken, err)
+		return nil, err
+	}
+	reply, err := readReply(c.r)
+	// ServerError — целый ответ, после него соединение пригодно
+	var serr ServerError
+	if err != nil && !errors.As(err, &serr) {
+		c.err = fmt.Errorf("%w: %w", ErrBroken, err)
+	}
+	return reply, err
+}
+
+// readReply разбирает ответ: простая строка и bulk-строка — string,
+// целое — int, массив — []any, null — nil, ошибка — ServerError. Любая
+// другая ошибка означает, что ответ прочитан не полностью
+func readReply(r *bufio.Reader) (any, error) {
+	return readReplyDepth(r, 0)
+}
+
+func readReplyDepth(r *bufio.Reader, depth int) (any, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, fmt.Errorf("%w: empty line", ErrUnexpectedReply)
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, ServerError(line[1:])
+	case ':':
+		return strconv.Atoi(line[1:])
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size > maxBulkLen {
+			return nil, fmt.Errorf("%w: invalid bulk length %q", ErrUnexpectedReply, line[1:])
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		return readBulk(r, size)
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n > maxArrayLen {
+			return nil, fmt.Errorf("%w: invalid array length %q", ErrUnexpectedReply, line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		if depth >= maxReplyDepth {
+			return nil, fmt.Errorf("%w: arrays nested too deep", ErrUnexpectedReply)
+		}
+		items := make([]any, 0, min(n, 1024))
+		// Ошибку сервера внутри массива запоминаем и дочитываем массив до конца
+		var itemErr error
+		for range n {
+			item, err := readReplyDepth(r, depth+1)
+			var serr ServerError
+			if errors.As(err, &serr) {
+				itemErr = cmp.Or(itemErr, err)
+			} else if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if itemErr != nil {
+			return nil, itemErr
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnexpectedReply, line)
+}
+
+// readBulk читает size байт и CRLF порциями, не выделяя заявленную длину заранее
+func readBulk(r *bufio.Reader, size int) (string, error) {
+	buf := make([]byte, 0, min(size, bulkChunkSize))
+	for len(buf) < size {
+		chunk := min(size-len(buf), bulkChunkSize)
+		buf = slices.Grow(buf, chunk)
+		n, err := io.ReadFull(r, buf[len(buf):len(buf)+chunk])
+		buf = buf[:len(buf)+n]
+		if err != nil {
+			return "", err
+		}
+	}
+	var crlf [2]byte
+	if _, err := io.ReadFull(r, crlf[:]); err != nil {
+		return "", err
+	}
+	if crlf != [2]byte{'\r', '\n'} {
+		return "", fmt.Errorf("%w: bulk string is not terminated by CRLF", ErrUnexpectedReply)
+	}
+	return string(buf), nil
+}
+
+// readLine читает строку до '\n', но не длиннее maxLineLen
+func readLine(r *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		part, err := r.ReadSlice('\n')
+		if len(line)+len(part) > maxLineLen {
+			return "", fmt.Errorf("%w: line too long", ErrUnexpectedReply)
+		}
+		line = append(line, part...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(line), "\r\n"), nil
+	}
+}
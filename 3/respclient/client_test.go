@@ -0,0 +1,155 @@
+package respclient
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestReadReply(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{name: "simple string", input: "+OK\r\n", expected: "OK"},
+		{name: "integer", input: ":-42\r\n", expected: -42},
+		{name: "bulk string", input: "$5\r\nhe\r\no\r\n", expected: "he\r\no"},
+		{name: "null bulk", input: "$-1\r\n", expected: nil},
+		{name: "array", input: "*2\r\n$1\r\na\r\n:1\r\n", expected: []any{"a", 1}},
+		{name: "empty array", input: "*0\r\n", expected: []any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readReply(bufio.NewReader(strings.NewReader(tt.input)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %#v, got %#v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReadReplyErrors(t *testing.T) {
+	_, err := readReply(bufio.NewReader(strings.NewReader("-ERR boom\r\n")))
+	var serr ServerError
+	if !errors.As(err, &serr) || serr != "ERR boom" {
+		t.Errorf("Expected ServerError, got %v", err)
+	}
+
+	_, err = readReply(bufio.NewReader(strings.NewReader("?\r\n")))
+	if !errors.Is(err, ErrUnexpectedReply) {
+		t.Errorf("Expected ErrUnexpectedReply, got %v", err)
+	}
+}
+
+// TestReadReplyLimits проверяет, что заявленные сервером длины не выделяются без проверки
+func TestReadReplyLimits(t *testing.T) {
+	for name, input := range map[string]string{
+		"huge bulk":     fmt.Sprintf("$%d\r\n", maxBulkLen+1),
+		"huge array":    fmt.Sprintf("*%d\r\n", maxArrayLen+1),
+		"max int array": fmt.Sprintf("*%d\r\n", math.MaxInt),
+		"deep nesting":  strings.Repeat("*1\r\n", maxReplyDepth+1) + ":1\r\n",
+		"long line":     "+" + strings.Repeat("x", maxLineLen) + "\r\n",
+	} {
+		if _, err := readReply(bufio.NewReader(strings.NewReader(input))); !errors.Is(err, ErrUnexpectedReply) {
+			t.Errorf("%s: expected ErrUnexpectedReply, got %v", name, err)
+		}
+	}
+
+	// Большая заявленная длина без данных не выделяет её целиком
+	input := fmt.Sprintf("$%d\r\nabc", maxBulkLen)
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	before := stats.TotalAlloc
+	readReply(bufio.NewReader(strings.NewReader(input)))
+	runtime.ReadMemStats(&stats)
+	if grown := stats.TotalAlloc - before; grown > 1<<20 {
+		t.Errorf("Truncated bulk allocated %d bytes", grown)
+	}
+}
+
+// TestReadReplyErrorInArray проверяет, что ошибка внутри массива не оставляет его хвост в потоке
+func TestReadReplyErrorInArray(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("*3\r\n:1\r\n-ERR item\r\n:3\r\n+NEXT\r\n"))
+	if _, err := readReply(r); err != ServerError("ERR item") {
+		t.Fatalf("Expected item error, got %v", err)
+	}
+	if got, err := readReply(r); got != "NEXT" || err != nil {
+		t.Errorf("Expected next reply NEXT, got %v (err: %v)", got, err)
+	}
+}
+
+// TestClientBrokenAfterPartialReply проверяет, что после оборванного ответа
+// клиент не читает его остаток как ответ следующей команды
+func TestClientBrokenAfterPartialReply(t *testing.T) {
+	server, conn := net.Pipe()
+	c := NewClient(conn)
+	defer c.Close()
+
+	go func() {
+		buf := make([]byte, 256)
+		server.Read(buf)
+		// Заявлен массив из двух ключей, а следом мусор вместо второго
+		server.Write([]byte("*2\r\n$1\r\na\r\n?\r\n:5\r\n"))
+	}()
+	if _, err := c.Keys("*"); !errors.Is(err, ErrUnexpectedReply) {
+		t.Fatalf("Expected ErrUnexpectedReply, got %v", err)
+	}
+	if _, err := c.IncrBy("a", 1); !errors.Is(err, ErrBroken) {
+		t.Errorf("Expected ErrBroken on the next call, got %v", err)
+	}
+}
+
+// TestClientServerErrorKeepsConnection проверяет, что ответ-ошибка не ломает клиента
+func TestClientServerErrorKeepsConnection(t *testing.T) {
+	server, conn := net.Pipe()
+	c := NewClient(conn)
+	defer c.Close()
+
+	go func() {
+		buf := make([]byte, 256)
+		for _, reply := range []string{"-ERR boom\r\n", ":2\r\n"} {
+			server.Read(buf)
+			server.Write([]byte(reply))
+		}
+	}()
+	if _, err := c.IncrBy("a", 1); err != ServerError("ERR boom") {
+		t.Fatalf("Expected ServerError, got %v", err)
+	}
+	if v, err := c.IncrBy("a", 1); err != nil || v != 2 {
+		t.Errorf("Expected 2 after server error, got %d (err: %v)", v, err)
+	}
+}
+
+// TestClientEncoding проверяет, что команда отправляется массивом bulk-строк
+func TestClientEncoding(t *testing.T) {
+	server, conn := net.Pipe()
+	c := NewClient(conn)
+	defer c.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 64)
+		n, _ := server.Read(buf)
+		received <- string(buf[:n])
+		server.Write([]byte(":7\r\n"))
+	}()
+
+	v, err := c.IncrBy("a b", 7)
+	if err != nil || v != 7 {
+		t.Fatalf("IncrBy: expected 7, got %d (err: %v)", v, err)
+	}
+	if got := <-received; got != "*3\r\n$6\r\nINCRBY\r\n$3\r\na b\r\n$1\r\n7\r\n" {
+		t.Errorf("Unexpected request encoding %q", got)
+	}
+}
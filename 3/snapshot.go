@@ -0,0 +1,300 @@
+package main
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	hamtBits  = 5
+	hamtWidth = 1 << hamtBits
+	hamtMask  = hamtWidth - 1
+)
+
+// hamtNode — узел неизменяемого префиксного дерева по хешу ключа (HAMT).
+// Слоты хранят *hamtNode или *hamtLeaf, bitmap отмечает занятые позиции
+type hamtNode struct {
+	bitmap uint32
+	slots  []any
+}
+
+// hamtLeaf хранит записи с одинаковым полным хешем
+type hamtLeaf struct {
+	hash    uint64
+	entries []Entry
+}
+
+type hamtRoot struct {
+	node *hamtNode
+	size int
+}
+
+// PersistentStringIntMap — потокобезопасная StringIntMap на неизменяемом HAMT.
+// Запись копирует только путь от корня до изменённого листа, поэтому Snapshot
+// стоит O(1): снимок просто сохраняет текущий корень и не меняется при
+// последующих записях. Чтения не берут блокировок
+type PersistentStringIntMap struct {
+	mtx  sync.Mutex // упорядочивает писателей
+	seed maphash.Seed
+	root atomic.Pointer[hamtRoot]
+}
+
+func NewPersistentStringIntMap() *PersistentStringIntMap {
+	m := &PersistentStringIntMap{seed: maphash.MakeSeed()}
+	m.root.Store(&hamtRoot{node: &hamtNode{}})
+	return m
+}
+
+func (m *PersistentStringIntMap) Add(key string, value int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	r := m.root.Load()
+	node, added := r.node.insert(maphash.String(m.seed, key), 0, key, value)
+	size := r.size
+	if added {
+		size++
+	}
+	m.root.Store(&hamtRoot{node: node, size: size})
+}
+
+func (m *PersistentStringIntMap) Remove(key string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	r := m.root.Load()
+	node, removed := r.node.remove(maphash.String(m.seed, key), 0, key)
+	if !removed {
+		return
+	}
+	if node == nil {
+		node = &hamtNode{}
+	}
+	m.root.Store(&hamtRoot{node: node, size: r.size - 1})
+}
+
+func (m *PersistentStringIntMap) Copy() map[string]int {
+	return m.Snapshot().Copy()
+}
+
+func (m *PersistentStringIntMap) Exists(key string) bool {
+	return m.Snapshot().Exists(key)
+}
+
+func (m *PersistentStringIntMap) Get(key string) (int, bool) {
+	return m.Snapshot().Get(key)
+}
+
+func (m *PersistentStringIntMap) Len() int {
+	return m.root.Load().size
+}
+
+// Snapshot возвращает неизменяемое представление мапы на текущий момент за O(1)
+func (m *PersistentStringIntMap) Snapshot() *MapSnapshot {
+	return &MapSnapshot{root: m.root.Load(), seed: m.seed}
+}
+
+// MapSnapshot — снимок PersistentStringIntMap только для чтения. Его можно
+// обходить из любых горутин, пока писатели изменяют исходную мапу
+type MapSnapshot struct {
+	root *hamtRoot
+	seed maphash.Seed
+}
+
+func (s *MapSnapshot) Get(key string) (int, bool) {
+	return s.root.node.get(maphash.String(s.seed, key), 0, key)
+}
+
+func (s *MapSnapshot) Exists(key string) bool {
+	_, ok := s.Get(key)
+	return ok
+}
+
+func (s *MapSnapshot) Len() int {
+	return s.root.size
+}
+
+// All обходит записи снимка в порядке дерева, то есть без определённого порядка ключей
+func (s *MapSnapshot) All() iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		s.root.node.walk(yield)
+	}
+}
+
+func (s *MapSnapshot) Copy() map[string]int {
+	newMap := make(map[string]int, s.root.size)
+	for k, v := range s.All() {
+		newMap[k] = v
+	}
+	return newMap
+}
+
+func (n *hamtNode) position(hash uint64, shift uint) (bit uint32, pos int) {
+	bit = 1 << ((hash >> shift) & hamtMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *hamtNode) get(hash uint64, shift uint, key string) (int, bool) {
+	for {
+		bit, pos := n.position(hash, shift)
+		if n.bitmap&bit == 0 {
+			return 0, false
+		}
+		switch s := n.slots[pos].(type) {
+		case *hamtNode:
+			n = s
+			shift += hamtBits
+		case *hamtLeaf:
+			if s.hash != hash {
+				return 0, false
+			}
+			for _, e := range s.entries {
+				if e.Key == key {
+					return e.Value, true
+				}
+			}
+			return 0, false
+		}
+	}
+}
+
+// insert возвращает новый узел с добавленной записью, исходный узел не меняется
+func (n *hamtNode) insert(hash uint64, shift uint, key string, value int) (*hamtNode, bool) {
+	bit, pos := n.position(hash, shift)
+	if n.bitmap&bit == 0 {
+		leaf := &hamtLeaf{hash: hash, entries: []Entry{{Key: key, Value: value}}}
+		return n.withInserted(bit, pos, leaf), true
+	}
+
+	switch s := n.slots[pos].(type) {
+	case *hamtNode:
+		child, added := s.insert(hash, shift+hamtBits, key, value)
+		return n.withReplaced(pos, child), added
+	case *hamtLeaf:
+		if s.hash == hash {
+			leaf, added := s.with(key, value)
+			return n.withReplaced(pos, leaf), added
+		}
+		// Хеши различаются, поэтому где-то ниже они разойдутся по разным слотам
+		child := (&hamtNode{}).withLeaf(s, shift+hamtBits)
+		child, _ = child.insert(hash, shift+hamtBits, key, value)
+		return n.withReplaced(pos, child), true
+	}
+	panic("unreachable")
+}
+
+// remove возвращает новый узел без записи; nil — узел стал пустым
+func (n *hamtNode) remove(hash uint64, shift uint, key string) (*hamtNode, bool) {
+	bit, pos := n.position(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	var replacement any
+	switch s := n.slots[pos].(type) {
+	case *hamtNode:
+		child, removed := s.remove(hash, shift+hamtBits, key)
+		if !removed {
+			return n, false
+		}
+		if child != nil {
+			replacement = child
+			// Узел с единственным листом заменяем самим листом
+			if len(child.slots) == 1 {
+				if leaf, ok := child.slots[0].(*hamtLeaf); ok {
+					replacement = leaf
+				}
+			}
+		}
+	case *hamtLeaf:
+		if s.hash != hash {
+			return n, false
+		}
+		leaf, removed := s.without(key)
+		if !removed {
+			return n, false
+		}
+		if leaf != nil {
+			replacement = leaf
+		}
+	}
+
+	if replacement != nil {
+		return n.withReplaced(pos, replacement), true
+	}
+	if len(n.slots) == 1 {
+		return nil, true
+	}
+	slots := make([]any, 0, len(n.slots)-1)
+	slots = append(slots, n.slots[:pos]...)
+	slots = append(slots, n.slots[pos+1:]...)
+	return &hamtNode{bitmap: n.bitmap &^ bit, slots: slots}, true
+}
+
+func (n *hamtNode) walk(yield func(string, int) bool) bool {
+	for _, slot := range n.slots {
+		switch s := slot.(type) {
+		case *hamtNode:
+			if !s.walk(yield) {
+				return false
+			}
+		case *hamtLeaf:
+			for _, e := range s.entries {
+				if !yield(e.Key, e.Value) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (n *hamtNode) withInserted(bit uint32, pos int, slot any) *hamtNode {
+	slots := make([]any, len(n.slots)+1)
+	copy(slots, n.slots[:pos])
+	slots[pos] = slot
+	copy(slots[pos+1:], n.slots[pos:])
+	return &hamtNode{bitmap: n.bitmap | bit, slots: slots}
+}
+
+func (n *hamtNode) withReplaced(pos int, slot any) *hamtNode {
+	slots := make([]any, len(n.slots))
+	copy(slots, n.slots)
+	slots[pos] = slot
+	return &hamtNode{bitmap: n.bitmap, slots: slots}
+}
+
+func (n *hamtNode) withLeaf(leaf *hamtLeaf, shift uint) *hamtNode {
+	bit, pos := n.position(leaf.hash, shift)
+	return n.withInserted(bit, pos, leaf)
+}
+
+func (l *hamtLeaf) with(key string, value int) (*hamtLeaf, bool) {
+	entries := make([]Entry, len(l.entries), len(l.entries)+1)
+	copy(entries, l.entries)
+	for i := range entries {
+		if entries[i].Key == key {
+			entries[i].Value = value
+			return &hamtLeaf{hash: l.hash, entries: entries}, false
+		}
+	}
+	entries = append(entries, Entry{Key: key, Value: value})
+	return &hamtLeaf{hash: l.hash, entries: entries}, true
+}
+
+func (l *hamtLeaf) without(key string) (*hamtLeaf, bool) {
+	for i, e := range l.entries {
+		if e.Key != key {
+			continue
+		}
+		if len(l.entries) == 1 {
+			return nil, true
+		}
+		entries := make([]Entry, 0, len(l.entries)-1)
+		entries = append(entries, l.entries[:i]...)
+		entries = append(entries, l.entries[i+1:]...)
+		return &hamtLeaf{hash: l.hash, entries: entries}, true
+	}
+	return l, false
+}
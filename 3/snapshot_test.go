@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestPersistentMapMatchesReference сверяет случайную последовательность операций с обычной мапой
+func TestPersistentMapMatchesReference(t *testing.T) {
+	m := NewPersistentStringIntMap()
+	ref := make(map[string]int)
+	rnd := rand.New(rand.NewPCG(3, 4))
+
+	for i := range 20000 {
+		key := fmt.Sprintf("key-%d", rnd.IntN(2000))
+		if rnd.IntN(3) == 0 {
+			m.Remove(key)
+			delete(ref, key)
+		} else {
+			m.Add(key, i)
+			ref[key] = i
+		}
+	}
+
+	if !reflect.DeepEqual(m.Copy(), ref) {
+		t.Fatal("Copy differs from reference map")
+	}
+	if m.Len() != len(ref) {
+		t.Errorf("Expected length %d, got %d", len(ref), m.Len())
+	}
+	for k, v := range ref {
+		if got, ok := m.Get(k); !ok || got != v {
+			t.Errorf("Get(%q): expected %d, got %d (exists: %v)", k, v, got, ok)
+		}
+	}
+
+	for k := range ref {
+		m.Remove(k)
+	}
+	if m.Len() != 0 || len(m.Copy()) != 0 {
+		t.Errorf("Expected empty map, got %v", m.Copy())
+	}
+}
+
+// TestSnapshotIsolation проверяет, что снимок не видит последующих изменений
+func TestSnapshotIsolation(t *testing.T) {
+	m := NewPersistentStringIntMap()
+	m.Add("first", 11)
+	m.Add("second", 22)
+
+	snap := m.Snapshot()
+
+	m.Add("third", 33)
+	m.Add("first", 100)
+	m.Remove("second")
+
+	if got := snap.Copy(); !reflect.DeepEqual(got, map[string]int{"first": 11, "second": 22}) {
+		t.Errorf("Snapshot was affected: %v", got)
+	}
+	if snap.Len() != 2 || snap.Exists("third") {
+		t.Errorf("Snapshot was affected: len %d", snap.Len())
+	}
+	if got := m.Copy(); !reflect.DeepEqual(got, map[string]int{"first": 100, "third": 33}) {
+		t.Errorf("Unexpected current state: %v", got)
+	}
+}
+
+// TestHAMTHashCollisions проверяет ключи с одинаковым полным хешем и с общим префиксом хеша
+func TestHAMTHashCollisions(t *testing.T) {
+	root := &hamtNode{}
+	root, _ = root.insert(0xABC, 0, "a", 1)
+	root, _ = root.insert(0xABC, 0, "b", 2)
+	root, _ = root.insert(0xABC|1<<60, 0, "c", 3)
+
+	for key, hash := range map[string]uint64{"a": 0xABC, "b": 0xABC, "c": 0xABC | 1<<60} {
+		if _, ok := root.get(hash, 0, key); !ok {
+			t.Errorf("Key %q not found", key)
+		}
+	}
+
+	root, _ = root.remove(0xABC, 0, "a")
+	root, _ = root.remove(0xABC|1<<60, 0, "c")
+	if v, ok := root.get(0xABC, 0, "b"); !ok || v != 2 {
+		t.Errorf("Expected b=2, got %d (exists: %v)", v, ok)
+	}
+	if _, ok := root.get(0xABC, 0, "a"); ok {
+		t.Error("a should be removed")
+	}
+	// После удаления путь к b должен схлопнуться до листа в корне
+	if _, ok := root.slots[0].(*hamtLeaf); !ok || len(root.slots) != 1 {
+		t.Errorf("Expected single leaf in root, got %#v", root.slots)
+	}
+}
+
+// TestSnapshotConcurrentReaders обходит снимки, пока писатели меняют мапу; имеет смысл с -race
+func TestSnapshotConcurrentReaders(t *testing.T) {
+	m := NewPersistentStringIntMap()
+	for i := range 100 {
+		m.Add(fmt.Sprint(i), 0)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 1000 {
+			m.Add(fmt.Sprint(i%100), i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 50 {
+			snap := m.Snapshot()
+			n := 0
+			for range snap.All() {
+				n++
+			}
+			if n != 100 {
+				t.Errorf("Snapshot iteration returned %d entries, expected 100", n)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func BenchmarkSnapshot(b *testing.B) {
+	m := NewPersistentStringIntMap()
+	for i := range 100000 {
+		m.Add(fmt.Sprint(i), i)
+	}
+	for b.Loop() {
+		m.Snapshot()
+	}
+}
+
+func BenchmarkFullCopy(b *testing.B) {
+	m := NewStringIntMap()
+	for i := range 100000 {
+		m.Add(fmt.Sprint(i), i)
+	}
+	for b.Loop() {
+		m.Copy()
+	}
+}
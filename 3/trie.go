@@ -0,0 +1,236 @@
+package main
+
+import "slices"
+
+type trieNode struct {
+	children map[byte]*trieNode
+	value    int
+	terminal bool // в узле заканчивается ключ
+}
+
+// TrieStringIntMap — StringIntMap на префиксном дереве. Стоимость операций
+// зависит от длины ключа, а не от размера мапы. Дополнительно поддерживает
+// выборку и удаление по префиксу и поиск по шаблону
+type TrieStringIntMap struct {
+	root   *trieNode
+	length int
+}
+
+func NewTrieStringIntMap() *TrieStringIntMap {
+	return &TrieStringIntMap{root: &trieNode{}}
+}
+
+func (m *TrieStringIntMap) Add(key string, value int) {
+	n := m.root
+	for i := 0; i < len(key); i++ {
+		if n.children == nil {
+			n.children = make(map[byte]*trieNode)
+		}
+		child, ok := n.children[key[i]]
+		if !ok {
+			child = &trieNode{}
+			n.children[key[i]] = child
+		}
+		n = child
+	}
+	if !n.terminal {
+		m.length++
+	}
+	n.value = value
+	n.terminal = true
+}
+
+func (m *TrieStringIntMap) Remove(key string) {
+	path := make([]*trieNode, 0, len(key)+1)
+	n := m.root
+	path = append(path, n)
+	for i := 0; i < len(key); i++ {
+		if n = n.children[key[i]]; n == nil {
+			return
+		}
+		path = append(path, n)
+	}
+	if !n.terminal {
+		return
+	}
+	n.terminal = false
+	n.value = 0
+	m.length--
+	m.prune(key, path)
+}
+
+func (m *TrieStringIntMap) Copy() map[string]int {
+	newMap := make(map[string]int, m.length)
+	m.root.walk(nil, func(key []byte, value int) {
+		newMap[string(key)] = value
+	})
+	return newMap
+}
+
+func (m *TrieStringIntMap) Exists(key string) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+func (m *TrieStringIntMap) Get(key string) (int, bool) {
+	n := m.find(key)
+	if n == nil || !n.terminal {
+		return 0, false
+	}
+	return n.value, true
+}
+
+func (m *TrieStringIntMap) Len() int {
+	return m.length
+}
+
+// KeysWithPrefix возвращает отсортированные ключи, начинающиеся с prefix
+func (m *TrieStringIntMap) KeysWithPrefix(prefix string) []string {
+	var keys []string
+	if n := m.find(prefix); n != nil {
+		n.walk([]byte(prefix), func(key []byte, _ int) {
+			keys = append(keys, string(key))
+		})
+	}
+	return keys
+}
+
+// RemovePrefix удаляет все ключи, начинающиеся с prefix, и возвращает их количество
+func (m *TrieStringIntMap) RemovePrefix(prefix string) int {
+	if prefix == "" {
+		removed := m.length
+		m.root = &trieNode{}
+		m.length = 0
+		return removed
+	}
+
+	path := []*trieNode{m.root}
+	n := m.root
+	for i := 0; i < len(prefix); i++ {
+		if n = n.children[prefix[i]]; n == nil {
+			return 0
+		}
+		path = append(path, n)
+	}
+	removed := 0
+	n.walk(nil, func([]byte, int) { removed++ })
+
+	parent := path[len(path)-2]
+	delete(parent.children, prefix[len(prefix)-1])
+	m.length -= removed
+	m.prune(prefix[:len(prefix)-1], path[:len(path)-1])
+	return removed
+}
+
+// LongestPrefixOf возвращает самый длинный ключ мапы, являющийся префиксом key
+func (m *TrieStringIntMap) LongestPrefixOf(key string) (string, int, bool) {
+	n := m.root
+	bestLen, bestValue, found := 0, 0, n.terminal
+	if found {
+		bestValue = n.value
+	}
+	for i := 0; i < len(key); i++ {
+		if n = n.children[key[i]]; n == nil {
+			break
+		}
+		if n.terminal {
+			bestLen, bestValue, found = i+1, n.value, true
+		}
+	}
+	if !found {
+		return "", 0, false
+	}
+	return key[:bestLen], bestValue, true
+}
+
+// Match возвращает отсортированные ключи, подходящие под шаблон: '*' —
+// любая последовательность байт, в том числе пустая, '?' — ровно один байт.
+// Например, "user:*:count"
+func (m *TrieStringIntMap) Match(pattern string) []string {
+	type state struct {
+		n *trieNode
+		p int
+	}
+	seen := make(map[state]bool)
+	var keys []string
+	var buf []byte
+
+	var match func(n *trieNode, p int)
+	match = func(n *trieNode, p int) {
+		if seen[state{n, p}] {
+			return
+		}
+		seen[state{n, p}] = true
+
+		if p == len(pattern) {
+			if n.terminal {
+				keys = append(keys, string(buf))
+			}
+			return
+		}
+		switch pattern[p] {
+		case '*':
+			match(n, p+1)
+			for _, b := range n.sortedLabels() {
+				buf = append(buf, b)
+				match(n.children[b], p)
+				buf = buf[:len(buf)-1]
+			}
+		case '?':
+			for _, b := range n.sortedLabels() {
+				buf = append(buf, b)
+				match(n.children[b], p+1)
+				buf = buf[:len(buf)-1]
+			}
+		default:
+			if child := n.children[pattern[p]]; child != nil {
+				buf = append(buf, pattern[p])
+				match(child, p+1)
+				buf = buf[:len(buf)-1]
+			}
+		}
+	}
+	match(m.root, 0)
+
+	// Порядок обхода с '*' не лексикографический: сначала идут более короткие совпадения
+	slices.Sort(keys)
+	return keys
+}
+
+func (m *TrieStringIntMap) find(key string) *trieNode {
+	n := m.root
+	for i := 0; i < len(key) && n != nil; i++ {
+		n = n.children[key[i]]
+	}
+	return n
+}
+
+// prune удаляет с конца пути узлы без ключей и потомков. path[i] — узел для key[:i]
+func (m *TrieStringIntMap) prune(key string, path []*trieNode) {
+	for i := len(path) - 1; i > 0; i-- {
+		n := path[i]
+		if n.terminal || len(n.children) > 0 {
+			return
+		}
+		delete(path[i-1].children, key[i-1])
+	}
+}
+
+func (n *trieNode) sortedLabels() []byte {
+	labels := make([]byte, 0, len(n.children))
+	for b := range n.children {
+		labels = append(labels, b)
+	}
+	slices.Sort(labels)
+	return labels
+}
+
+// walk обходит ключи поддерева в лексикографическом порядке, prefix — ключ самого узла
+func (n *trieNode) walk(prefix []byte, visit func(key []byte, value int)) {
+	if n.terminal {
+		visit(prefix, n.value)
+	}
+	for _, b := range n.sortedLabels() {
+		n.children[b].walk(append(prefix, b), visit)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func newTrieFrom(keys ...string) *TrieStringIntMap {
+	m := NewTrieStringIntMap()
+	for i, k := range keys {
+		m.Add(k, i)
+	}
+	return m
+}
+
+// TestTrieMapBasic проверяет контракт StringIntMapInterface, включая пустой ключ и вложенные ключи
+func TestTrieMapBasic(t *testing.T) {
+	var m StringIntMapInterface = NewTrieStringIntMap()
+	m.Add("user", 1)
+	m.Add("user:1", 2)
+	m.Add("", 3)
+	m.Add("user", 10)
+
+	expected := map[string]int{"user": 10, "user:1": 2, "": 3}
+	if got := m.Copy(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if m.Exists("use") {
+		t.Error("Inner node without key should not exist")
+	}
+
+	m.Remove("user")
+	m.Remove("missing")
+	if m.Exists("user") || !m.Exists("user:1") {
+		t.Errorf("Unexpected state after Remove: %v", m.Copy())
+	}
+	m.Remove("user:1")
+
+	trie := m.(*TrieStringIntMap)
+	if trie.Len() != 1 || len(trie.root.children) != 0 {
+		t.Errorf("Expected pruned trie with only empty key, got len %d", trie.Len())
+	}
+}
+
+func TestTrieKeysWithPrefix(t *testing.T) {
+	m := newTrieFrom("user:2:count", "user:1:count", "user:1:last", "admin", "user")
+
+	got := m.KeysWithPrefix("user:1:")
+	if !slices.Equal(got, []string{"user:1:count", "user:1:last"}) {
+		t.Errorf("Unexpected keys with prefix: %v", got)
+	}
+	got = m.KeysWithPrefix("")
+	if !slices.Equal(got, []string{"admin", "user", "user:1:count", "user:1:last", "user:2:count"}) {
+		t.Errorf("Expected all keys sorted, got %v", got)
+	}
+	if got := m.KeysWithPrefix("nobody"); len(got) != 0 {
+		t.Errorf("Expected no keys, got %v", got)
+	}
+}
+
+func TestTrieRemovePrefix(t *testing.T) {
+	m := newTrieFrom("user:1:count", "user:1:last", "user:2:count", "user:1")
+
+	if n := m.RemovePrefix("user:1:"); n != 2 {
+		t.Errorf("Expected 2 removed keys, got %d", n)
+	}
+	if got := m.KeysWithPrefix(""); !slices.Equal(got, []string{"user:1", "user:2:count"}) {
+		t.Errorf("Unexpected keys after RemovePrefix: %v", got)
+	}
+	if n := m.RemovePrefix("missing"); n != 0 {
+		t.Errorf("Expected 0 removed keys, got %d", n)
+	}
+	if n := m.RemovePrefix(""); n != 2 || m.Len() != 0 {
+		t.Errorf("Expected all keys removed, got %d, len %d", n, m.Len())
+	}
+}
+
+func TestTrieLongestPrefixOf(t *testing.T) {
+	m := newTrieFrom("/api", "/api/v1", "/api/v1/users")
+
+	tests := []struct {
+		key      string
+		expected string
+		found    bool
+	}{
+		{key: "/api/v1/users/42", expected: "/api/v1/users", found: true},
+		{key: "/api/v2", expected: "/api", found: true},
+		{key: "/api/v1", expected: "/api/v1", found: true},
+		{key: "/static", found: false},
+	}
+
+	for _, tt := range tests {
+		got, _, ok := m.LongestPrefixOf(tt.key)
+		if got != tt.expected || ok != tt.found {
+			t.Errorf("LongestPrefixOf(%q) = %q, %v; expected %q, %v", tt.key, got, ok, tt.expected, tt.found)
+		}
+	}
+}
+
+func TestTrieMatch(t *testing.T) {
+	m := newTrieFrom("user:1:count", "user:22:count", "user:1:last", "user::count", "users", "user:1:count:x")
+
+	tests := []struct {
+		pattern  string
+		expected []string
+	}{
+		{pattern: "user:*:count", expected: []string{"user:1:count", "user:22:count", "user::count"}},
+		{pattern: "user:?:*", expected: []string{"user:1:count", "user:1:count:x", "user:1:last"}},
+		{pattern: "users", expected: []string{"users"}},
+		{pattern: "*s", expected: []string{"users"}},
+		{pattern: "**count", expected: []string{"user:1:count", "user:22:count", "user::count"}},
+		{pattern: "admin*", expected: nil},
+	}
+
+	for _, tt := range tests {
+		if got := m.Match(tt.pattern); !slices.Equal(got, tt.expected) {
+			t.Errorf("Match(%q) = %v; expected %v", tt.pattern, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	ErrConflict = errors.New("transaction: conflict with concurrent write")
+	ErrTxDone   = errors.New("transaction: already committed or rolled back")
+)
+
+// TxStringIntMap — потокобезопасная StringIntMap с транзакциями. У каждого
+// ключа есть версия из общего растущего счётчика, которая меняется при любой
+// записи. У отсутствующего ключа версия 0, поэтому удаление стирает её и память
+// не растёт от короткоживущих ключей. Транзакция запоминает версии прочитанных
+// и изменённых ключей и при Commit проверяет, что их никто не изменил
+// (оптимистичная блокировка)
+type TxStringIntMap struct {
+	mtx      sync.RWMutex
+	data     map[string]int
+	versions map[string]uint64
+	clock    uint64
+}
+
+func NewTxStringIntMap() *TxStringIntMap {
+	return &TxStringIntMap{
+		data:     make(map[string]int),
+		versions: make(map[string]uint64),
+	}
+}
+
+func (m *TxStringIntMap) Add(key string, value int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.set(key, value, true)
+}
+
+func (m *TxStringIntMap) Remove(key string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if _, ok := m.data[key]; ok {
+		m.set(key, 0, false)
+	}
+}
+
+func (m *TxStringIntMap) Copy() map[string]int {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	newMap := make(map[string]int, len(m.data))
+	for k, v := range m.data {
+		newMap[k] = v
+	}
+	return newMap
+}
+
+func (m *TxStringIntMap) Exists(key string) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+func (m *TxStringIntMap) Get(key string) (int, bool) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	val, ok := m.data[key]
+	return val, ok
+}
+
+// Begin начинает транзакцию. Изменения копятся в транзакции и не видны
+// другим до Commit
+func (m *TxStringIntMap) Begin() *Tx {
+	return &Tx{
+		m:      m,
+		reads:  make(map[string]uint64),
+		writes: make(map[string]txWrite),
+	}
+}
+
+func (m *TxStringIntMap) set(key string, value int, present bool) {
+	if present {
+		m.clock++
+		m.versions[key] = m.clock
+		m.data[key] = value
+	} else {
+		delete(m.versions, key)
+		delete(m.data, key)
+	}
+}
+
+type txWrite struct {
+	value   int
+	present bool // false — ключ удаляется
+}
+
+type Tx struct {
+	m      *TxStringIntMap
+	reads  map[string]uint64 // версии ключей на момент первого обращения
+	writes map[string]txWrite
+	done   bool
+}
+
+// Get читает ключ с учётом собственных изменений транзакции
+func (tx *Tx) Get(key string) (int, bool) {
+	if w, ok := tx.writes[key]; ok {
+		return w.value, w.present
+	}
+	tx.m.mtx.RLock()
+	defer tx.m.mtx.RUnlock()
+	tx.track(key)
+	val, ok := tx.m.data[key]
+	return val, ok
+}
+
+func (tx *Tx) Exists(key string) bool {
+	_, ok := tx.Get(key)
+	return ok
+}
+
+func (tx *Tx) Add(key string, value int) {
+	tx.write(key, txWrite{value: value, present: true})
+}
+
+func (tx *Tx) Remove(key string) {
+	tx.write(key, txWrite{})
+}
+
+// Copy возвращает содержимое мапы с применёнными изменениями транзакции.
+// Для проверки конфликтов чтение всей мапы не отслеживается
+func (tx *Tx) Copy() map[string]int {
+	newMap := tx.m.Copy()
+	for k, w := range tx.writes {
+		if w.present {
+			newMap[k] = w.value
+		} else {
+			delete(newMap, k)
+		}
+	}
+	return newMap
+}
+
+// Commit атомарно применяет изменения. Если любой прочитанный или
+// изменённый ключ успели изменить другие, возвращает ErrConflict и ничего не применяет
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+
+	tx.m.mtx.Lock()
+	defer tx.m.mtx.Unlock()
+	for key, version := range tx.reads {
+		if tx.m.versions[key] != version {
+			return ErrConflict
+		}
+	}
+	for key, w := range tx.writes {
+		tx.m.set(key, w.value, w.present)
+	}
+	return nil
+}
+
+// Rollback отменяет транзакцию
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	tx.writes = nil
+	return nil
+}
+
+func (tx *Tx) write(key string, w txWrite) {
+	if tx.done {
+		return
+	}
+	if _, ok := tx.reads[key]; !ok {
+		tx.m.mtx.RLock()
+		tx.track(key)
+		tx.m.mtx.RUnlock()
+	}
+	tx.writes[key] = w
+}
+
+// track вызывается с захваченной блокировкой мапы
+func (tx *Tx) track(key string) {
+	if _, ok := tx.reads[key]; !ok {
+		tx.reads[key] = tx.m.versions[key]
+	}
+}
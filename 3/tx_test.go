@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// transfer переводит amount с одного ключа на другой, повторяя транзакцию при конфликте
+func transfer(m *TxStringIntMap, from, to string, amount int) {
+	for {
+		tx := m.Begin()
+		src, _ := tx.Get(from)
+		dst, _ := tx.Get(to)
+		tx.Add(from, src-amount)
+		tx.Add(to, dst+amount)
+		if err := tx.Commit(); !errors.Is(err, ErrConflict) {
+			return
+		}
+	}
+}
+
+// TestTxReadYourWrites проверяет, что транзакция видит свои изменения, а другие — нет
+func TestTxReadYourWrites(t *testing.T) {
+	m := NewTxStringIntMap()
+	m.Add("a", 1)
+	m.Add("b", 2)
+
+	tx := m.Begin()
+	tx.Add("a", 10)
+	tx.Remove("b")
+	tx.Add("c", 3)
+
+	if v, ok := tx.Get("a"); !ok || v != 10 {
+		t.Errorf("Tx should see own write a=10, got %d (exists: %v)", v, ok)
+	}
+	if tx.Exists("b") {
+		t.Error("Tx should see own remove of b")
+	}
+	if got := tx.Copy(); !reflect.DeepEqual(got, map[string]int{"a": 10, "c": 3}) {
+		t.Errorf("Unexpected tx view: %v", got)
+	}
+	if v, _ := m.Get("a"); v != 1 || !m.Exists("b") || m.Exists("c") {
+		t.Errorf("Uncommitted changes leaked: %v", m.Copy())
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Copy(); !reflect.DeepEqual(got, map[string]int{"a": 10, "c": 3}) {
+		t.Errorf("Unexpected map after commit: %v", got)
+	}
+	if err := tx.Commit(); !errors.Is(err, ErrTxDone) {
+		t.Errorf("Expected ErrTxDone on second commit, got %v", err)
+	}
+}
+
+func TestTxRollback(t *testing.T) {
+	m := NewTxStringIntMap()
+	m.Add("a", 1)
+
+	tx := m.Begin()
+	tx.Add("a", 2)
+	tx.Remove("a")
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); !errors.Is(err, ErrTxDone) {
+		t.Errorf("Expected ErrTxDone after rollback, got %v", err)
+	}
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Errorf("Rollback changed the map: a=%d (exists: %v)", v, ok)
+	}
+}
+
+// TestTxConflict проверяет обнаружение конфликта с записью вне транзакции
+func TestTxConflict(t *testing.T) {
+	tests := []struct {
+		name  string
+		touch func(tx *Tx)
+		write func(m *TxStringIntMap)
+	}{
+		{
+			name:  "read then concurrent add",
+			touch: func(tx *Tx) { tx.Get("a") },
+			write: func(m *TxStringIntMap) { m.Add("a", 5) },
+		},
+		{
+			name:  "write then concurrent remove",
+			touch: func(tx *Tx) { tx.Add("a", 7) },
+			write: func(m *TxStringIntMap) { m.Remove("a") },
+		},
+		{
+			name:  "read then concurrent remove and re-add",
+			touch: func(tx *Tx) { tx.Get("a") },
+			write: func(m *TxStringIntMap) { m.Remove("a"); m.Add("a", 1) },
+		},
+		{
+			name:  "read missing key then concurrent add",
+			touch: func(tx *Tx) { tx.Exists("new") },
+			write: func(m *TxStringIntMap) { m.Add("new", 1) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewTxStringIntMap()
+			m.Add("a", 1)
+
+			tx := m.Begin()
+			tt.touch(tx)
+			tx.Add("result", 1)
+			tt.write(m)
+
+			if err := tx.Commit(); !errors.Is(err, ErrConflict) {
+				t.Errorf("Expected ErrConflict, got %v", err)
+			}
+			if m.Exists("result") {
+				t.Error("Conflicting transaction was partially applied")
+			}
+		})
+	}
+}
+
+// TestTxRemovedKeysReleaseVersions проверяет, что удалённые ключи не оставляют версий
+func TestTxRemovedKeysReleaseVersions(t *testing.T) {
+	m := NewTxStringIntMap()
+	for i := range 1000 {
+		key := strconv.Itoa(i)
+		m.Add(key, i)
+		tx := m.Begin()
+		tx.Remove(key)
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(m.versions) != 0 {
+		t.Errorf("Expected no versions for removed keys, got %d", len(m.versions))
+	}
+}
+
+// TestTxConcurrentTransfers проверяет, что параллельные переводы сохраняют сумму
+func TestTxConcurrentTransfers(t *testing.T) {
+	m := NewTxStringIntMap()
+	accounts := []string{"alice", "bob", "carol"}
+	for _, a := range accounts {
+		m.Add(a, 1000)
+	}
+
+	var wg sync.WaitGroup
+	for g := range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				from := accounts[(g+i)%len(accounts)]
+				to := accounts[(g+i+1)%len(accounts)]
+				transfer(m, from, to, i%7)
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for _, v := range m.Copy() {
+		total += v
+	}
+	if total != 3000 {
+		t.Errorf("Expected total 3000 after transfers, got %d", total)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type ChangeOp int
+
+const (
+	OpAdd ChangeOp = iota
+	OpRemove
+)
+
+type ChangeEvent struct {
+	Op       ChangeOp
+	Key      string
+	OldValue int
+	HadOld   bool // был ли ключ в мапе до изменения
+	NewValue int  // для OpRemove всегда 0
+}
+
+// SlowConsumerPolicy определяет, что делать, если буфер подписчика заполнен
+type SlowConsumerPolicy int
+
+const (
+	PolicyBlock      SlowConsumerPolicy = iota // ждать, пока подписчик прочитает событие
+	PolicyDrop                                 // пропустить событие и увеличить счётчик Dropped
+	PolicyDisconnect                           // отписать подписчика и закрыть его канал
+)
+
+type WatchOptions struct {
+	Buffer int
+	Policy SlowConsumerPolicy
+}
+
+type Subscription struct {
+	ch     chan ChangeEvent
+	prefix string
+	policy SlowConsumerPolicy
+	done   chan struct{}
+	once   sync.Once
+	m      *WatchableStringIntMap
+
+	// sendMtx удерживается на время отправки в ch, чтобы канал не закрылся во время неё
+	sendMtx sync.Mutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+// Events возвращает канал событий, он закрывается после отписки
+func (s *Subscription) Events() <-chan ChangeEvent {
+	return s.ch
+}
+
+// Unsubscribe отписывает подписчика и закрывает канал событий. Повторный вызов ничего не делает
+func (s *Subscription) Unsubscribe() {
+	// Закрытие done освобождает рассылку, ждущую этого подписчика, и она отпускает sendMtx
+	s.once.Do(func() { close(s.done) })
+	s.m.watchMtx.Lock()
+	delete(s.m.subs, s)
+	s.m.watchMtx.Unlock()
+	s.closeChannel()
+}
+
+// Dropped возвращает количество пропущенных событий при PolicyDrop
+func (s *Subscription) Dropped() int {
+	return int(s.dropped.Load())
+}
+
+func (s *Subscription) closeChannel() {
+	s.sendMtx.Lock()
+	defer s.sendMtx.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// WatchableStringIntMap оборачивает любую StringIntMapInterface и рассылает
+// подписчикам события об изменениях. Обёртка потокобезопасна. Каждое изменение
+// получает номер под блокировкой данных, а рассылки идут строго по номерам и
+// без удержания блокировок данных и списка подписчиков. Поэтому подписчик может
+// читать мапу, подписываться и отписываться, не опасаясь взаимной блокировки.
+// Писать в мапу из подписчика с PolicyBlock нельзя: запись будет ждать своей
+// очереди за рассылкой, которая ждёт этого же подписчика
+type WatchableStringIntMap struct {
+	mtx   sync.RWMutex
+	inner StringIntMapInterface
+	seq   uint64 // номер следующего изменения, под mtx
+
+	turnMtx sync.Mutex
+	turn    *sync.Cond
+	current uint64 // номер изменения, чья рассылка идёт сейчас, под turnMtx
+
+	watchMtx sync.Mutex
+	subs     map[*Subscription]struct{}
+}
+
+func NewWatchableStringIntMap(inner StringIntMapInterface) *WatchableStringIntMap {
+	if inner == nil {
+		inner = NewStringIntMap()
+	}
+	m := &WatchableStringIntMap{
+		inner: inner,
+		subs:  make(map[*Subscription]struct{}),
+	}
+	m.turn = sync.NewCond(&m.turnMtx)
+	return m
+}
+
+// Watch подписывается на изменения ключей, начинающихся с prefix
+func (m *WatchableStringIntMap) Watch(prefix string, opts WatchOptions) *Subscription {
+	s := &Subscription{
+		ch:     make(chan ChangeEvent, max(opts.Buffer, 0)),
+		prefix: prefix,
+		policy: opts.Policy,
+		done:   make(chan struct{}),
+		m:      m,
+	}
+	m.watchMtx.Lock()
+	defer m.watchMtx.Unlock()
+	m.subs[s] = struct{}{}
+	return s
+}
+
+func (m *WatchableStringIntMap) Add(key string, value int) {
+	m.mtx.Lock()
+	old, had := m.inner.Get(key)
+	m.inner.Add(key, value)
+	seq := m.nextSeq()
+	m.mtx.Unlock()
+	m.publish(seq, ChangeEvent{Op: OpAdd, Key: key, OldValue: old, HadOld: had, NewValue: value})
+}
+
+func (m *WatchableStringIntMap) Remove(key string) {
+	m.mtx.Lock()
+	old, had := m.inner.Get(key)
+	if !had {
+		m.mtx.Unlock()
+		return
+	}
+	m.inner.Remove(key)
+	seq := m.nextSeq()
+	m.mtx.Unlock()
+	m.publish(seq, ChangeEvent{Op: OpRemove, Key: key, OldValue: old, HadOld: true})
+}
+
+func (m *WatchableStringIntMap) Copy() map[string]int {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return m.inner.Copy()
+}
+
+func (m *WatchableStringIntMap) Exists(key string) bool {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return m.inner.Exists(key)
+}
+
+func (m *WatchableStringIntMap) Get(key string) (int, bool) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return m.inner.Get(key)
+}
+
+// nextSeq выдаёт номер очереди на рассылку. Вызывается с захваченной m.mtx,
+// так что номера совпадают с порядком изменений
+func (m *WatchableStringIntMap) nextSeq() uint64 {
+	seq := m.seq
+	m.seq++
+	return seq
+}
+
+func (m *WatchableStringIntMap) waitTurn(seq uint64) {
+	m.turnMtx.Lock()
+	for m.current != seq {
+		m.turn.Wait()
+	}
+	m.turnMtx.Unlock()
+}
+
+func (m *WatchableStringIntMap) endTurn() {
+	m.turnMtx.Lock()
+	m.current++
+	m.turn.Broadcast()
+	m.turnMtx.Unlock()
+}
+
+// publish дожидается очереди seq и рассылает событие. Список подписчиков
+// копируется под watchMtx, а отправка идёт под sendMtx конкретного подписчика
+func (m *WatchableStringIntMap) publish(seq uint64, ev ChangeEvent) {
+	m.waitTurn(seq)
+	defer m.endTurn()
+
+	m.watchMtx.Lock()
+	var targets []*Subscription
+	for s := range m.subs {
+		if strings.HasPrefix(ev.Key, s.prefix) {
+			targets = append(targets, s)
+		}
+	}
+	m.watchMtx.Unlock()
+
+	for _, s := range targets {
+		if !m.deliver(s, ev) {
+			// PolicyDisconnect: отписываем медленного подписчика
+			s.once.Do(func() { close(s.done) })
+			m.watchMtx.Lock()
+			delete(m.subs, s)
+			m.watchMtx.Unlock()
+			s.closeChannel()
+		}
+	}
+}
+
+// deliver отправляет событие по политике подписчика и возвращает false, если его нужно отключить
+func (m *WatchableStringIntMap) deliver(s *Subscription, ev ChangeEvent) bool {
+	s.sendMtx.Lock()
+	defer s.sendMtx.Unlock()
+	if s.closed {
+		return true
+	}
+	switch s.policy {
+	case PolicyBlock:
+		select {
+		case s.ch <- ev:
+		case <-s.done:
+		}
+	case PolicyDrop:
+		select {
+		case s.ch <- ev:
+		default:
+			s.dropped.Add(1)
+		}
+	case PolicyDisconnect:
+		select {
+		case s.ch <- ev:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func recvEvent(t *testing.T, ch <-chan ChangeEvent) (ChangeEvent, bool) {
+	t.Helper()
+	select {
+	case ev, ok := <-ch:
+		return ev, ok
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for change event")
+		return ChangeEvent{}, false
+	}
+}
+
+// TestWatchEvents проверяет события Add и Remove и фильтр по префиксу
+func TestWatchEvents(t *testing.T) {
+	m := NewWatchableStringIntMap(nil)
+	sub := m.Watch("user:", WatchOptions{Buffer: 10})
+	defer sub.Unsubscribe()
+
+	m.Add("user:1", 10)
+	m.Add("other", 5)
+	m.Add("user:1", 20)
+	m.Remove("user:1")
+	m.Remove("user:missing")
+
+	expected := []ChangeEvent{
+		{Op: OpAdd, Key: "user:1", NewValue: 10},
+		{Op: OpAdd, Key: "user:1", OldValue: 10, HadOld: true, NewValue: 20},
+		{Op: OpRemove, Key: "user:1", OldValue: 20, HadOld: true},
+	}
+	for i, exp := range expected {
+		ev, _ := recvEvent(t, sub.Events())
+		if ev != exp {
+			t.Errorf("Event %d: expected %+v, got %+v", i, exp, ev)
+		}
+	}
+
+	select {
+	case ev := <-sub.Events():
+		t.Errorf("Unexpected extra event %+v", ev)
+	default:
+	}
+}
+
+func TestWatchUnsubscribeClosesChannel(t *testing.T) {
+	m := NewWatchableStringIntMap(NewSyncStringIntMap())
+	sub := m.Watch("", WatchOptions{})
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+
+	if _, ok := recvEvent(t, sub.Events()); ok {
+		t.Error("Channel should be closed after Unsubscribe")
+	}
+	m.Add("key", 1) // после отписки не должно быть паники или блокировки
+}
+
+// TestWatchBlockPolicy проверяет, что блокирующий подписчик получает все события и не мешает чтению мапы
+func TestWatchBlockPolicy(t *testing.T) {
+	m := NewWatchableStringIntMap(nil)
+	sub := m.Watch("", WatchOptions{Policy: PolicyBlock})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 5 {
+			m.Add("key", i)
+		}
+	}()
+
+	for i := range 5 {
+		ev, _ := recvEvent(t, sub.Events())
+		if ev.NewValue != i {
+			t.Errorf("Expected value %d, got %d", i, ev.NewValue)
+		}
+		m.Get("key")
+	}
+	<-done
+	sub.Unsubscribe()
+}
+
+// TestWatchBlockConcurrentWriters проверяет, что блокирующий подписчик, читающий
+// мапу между событиями, не вызывает взаимной блокировки с несколькими писателями,
+// а события каждого писателя приходят по порядку
+func TestWatchBlockConcurrentWriters(t *testing.T) {
+	const writers, perWriter = 4, 200
+	m := NewWatchableStringIntMap(nil)
+	sub := m.Watch("", WatchOptions{Policy: PolicyBlock})
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("writer:%d", w)
+			for i := range perWriter {
+				m.Add(key, i)
+			}
+		}()
+	}
+
+	last := make(map[string]int)
+	for range writers * perWriter {
+		ev, _ := recvEvent(t, sub.Events())
+		if prev, ok := last[ev.Key]; ok && ev.NewValue != prev+1 {
+			t.Fatalf("%s: event %d after %d", ev.Key, ev.NewValue, prev)
+		}
+		last[ev.Key] = ev.NewValue
+		// Чтение, подписка и отписка из подписчика не должны блокироваться
+		m.Get(ev.Key)
+		m.Copy()
+		m.Watch("other:", WatchOptions{}).Unsubscribe()
+	}
+	wg.Wait()
+	sub.Unsubscribe()
+}
+
+// TestWatchBlockedUnsubscribe проверяет, что отписка освобождает заблокированного писателя
+func TestWatchBlockedUnsubscribe(t *testing.T) {
+	m := NewWatchableStringIntMap(nil)
+	sub := m.Watch("", WatchOptions{Policy: PolicyBlock})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Add("key", 1)
+	}()
+
+	// Ждём, пока писатель изменит данные и заблокируется на доставке
+	for !m.Exists("key") {
+		runtime.Gosched()
+	}
+	sub.Unsubscribe()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Add stayed blocked after Unsubscribe")
+	}
+}
+
+func TestWatchDropPolicy(t *testing.T) {
+	m := NewWatchableStringIntMap(nil)
+	sub := m.Watch("", WatchOptions{Buffer: 2, Policy: PolicyDrop})
+	defer sub.Unsubscribe()
+
+	for i := range 5 {
+		m.Add("key", i)
+	}
+
+	if sub.Dropped() != 3 {
+		t.Errorf("Expected 3 dropped events, got %d", sub.Dropped())
+	}
+	for i := range 2 {
+		if ev, _ := recvEvent(t, sub.Events()); ev.NewValue != i {
+			t.Errorf("Expected buffered value %d, got %d", i, ev.NewValue)
+		}
+	}
+}
+
+func TestWatchDisconnectPolicy(t *testing.T) {
+	m := NewWatchableStringIntMap(nil)
+	slow := m.Watch("", WatchOptions{Buffer: 1, Policy: PolicyDisconnect})
+	fast := m.Watch("", WatchOptions{Buffer: 10})
+	defer fast.Unsubscribe()
+
+	m.Add("a", 1)
+	m.Add("b", 2)
+
+	if ev, ok := recvEvent(t, slow.Events()); !ok || ev.Key != "a" {
+		t.Errorf("Expected buffered event for a, got %+v (ok: %v)", ev, ok)
+	}
+	if _, ok := recvEvent(t, slow.Events()); ok {
+		t.Error("Slow consumer should be disconnected")
+	}
+	slow.Unsubscribe()
+
+	for _, key := range []string{"a", "b"} {
+		if ev, _ := recvEvent(t, fast.Events()); ev.Key != key {
+			t.Errorf("Fast consumer: expected %s, got %s", key, ev.Key)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+// Bag — мультимножество: каждому элементу сопоставлено число вхождений.
+// Элементы с нулевым счётчиком не хранятся. Нулевое значение готово к использованию.
+// Арифметические операции возвращают новый Bag и не меняют исходные
+type Bag[T comparable] struct {
+	counts map[T]int
+	total  int
+}
+
+func NewBag[T comparable](items ...T) *Bag[T] {
+	b := &Bag[T]{counts: make(map[T]int, len(items))}
+	for _, item := range items {
+		b.AddN(item, 1)
+	}
+	return b
+}
+
+func (b *Bag[T]) Add(item T) {
+	b.AddN(item, 1)
+}
+
+// AddN добавляет n вхождений item, n <= 0 игнорируется
+func (b *Bag[T]) AddN(item T, n int) {
+	if n <= 0 {
+		return
+	}
+	if b.counts == nil {
+		b.counts = make(map[T]int)
+	}
+	b.counts[item] += n
+	b.total += n
+}
+
+// Remove убирает одно вхождение item и сообщает, было ли оно
+func (b *Bag[T]) Remove(item T) bool {
+	return b.RemoveN(item, 1) == 1
+}
+
+// RemoveN убирает до n вхождений item и возвращает, сколько убрано
+func (b *Bag[T]) RemoveN(item T, n int) int {
+	c := b.counts[item]
+	removed := min(max(n, 0), c)
+	if removed == c {
+		delete(b.counts, item)
+	} else {
+		b.counts[item] = c - removed
+	}
+	b.total -= removed
+	return removed
+}
+
+func (b *Bag[T]) Count(item T) int {
+	return b.counts[item]
+}
+
+// Len возвращает общее число вхождений
+func (b *Bag[T]) Len() int {
+	return b.total
+}
+
+// Distinct возвращает число различных элементов
+func (b *Bag[T]) Distinct() int {
+	return len(b.counts)
+}
+
+// Counts возвращает копию счётчиков
+func (b *Bag[T]) Counts() map[T]int {
+	counts := make(map[T]int, len(b.counts))
+	for item, c := range b.counts {
+		counts[item] = c
+	}
+	return counts
+}
+
+// Sum складывает счётчики: {a:2} + {a:1, b:1} = {a:3, b:1}
+func (b *Bag[T]) Sum(other *Bag[T]) *Bag[T] {
+	return b.combine(other, func(x, y int) int { return x + y })
+}
+
+// Subtract вычитает счётчики, не опускаясь ниже нуля: {a:2, b:1} - {a:1, b:3} = {a:1}
+func (b *Bag[T]) Subtract(other *Bag[T]) *Bag[T] {
+	return b.combine(other, func(x, y int) int { return x - y })
+}
+
+// Union берёт наибольший счётчик: {a:2} ∪ {a:1, b:1} = {a:2, b:1}
+func (b *Bag[T]) Union(other *Bag[T]) *Bag[T] {
+	return b.combine(other, func(x, y int) int { return max(x, y) })
+}
+
+// Intersection берёт наименьший счётчик: {a:2, b:1} ∩ {a:1} = {a:1}
+func (b *Bag[T]) Intersection(other *Bag[T]) *Bag[T] {
+	return b.combine(other, func(x, y int) int { return min(x, y) })
+}
+
+// IsSubBag сообщает, что каждый элемент b встречается в other не реже
+func (b *Bag[T]) IsSubBag(other *Bag[T]) bool {
+	if b.total > other.total {
+		return false
+	}
+	for item, c := range b.counts {
+		if other.counts[item] < c {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Bag[T]) Equal(other *Bag[T]) bool {
+	return b.total == other.total && b.Distinct() == other.Distinct() && b.IsSubBag(other)
+}
+
+// combine применяет op к счётчикам каждого элемента обоих мультимножеств
+func (b *Bag[T]) combine(other *Bag[T], op func(x, y int) int) *Bag[T] {
+	res := NewBag[T]()
+	for item, c := range b.counts {
+		res.AddN(item, op(c, other.counts[item]))
+	}
+	for item, c := range other.counts {
+		if _, seen := b.counts[item]; !seen {
+			res.AddN(item, op(0, c))
+		}
+	}
+	return res
+}
+
+// DifferenceMultiset возвращает s1 без элементов s2 с учётом кратности: каждое
+// вхождение в s2 отменяет ровно одно вхождение в s1, начиная с самого раннего.
+// Порядок оставшихся элементов сохраняется
+func DifferenceMultiset[T comparable](s1, s2 []T) []T {
+	cancel := NewBag(s2...)
+	var result []T
+	for _, item := range s1 {
+		if !cancel.Remove(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
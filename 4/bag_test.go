@@ -0,0 +1,89 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestBagCounts(t *testing.T) {
+	var b Bag[string]
+	b.Add("apple")
+	b.AddN("banana", 3)
+	b.AddN("cherry", 0)
+
+	if b.Count("banana") != 3 || b.Count("cherry") != 0 || b.Len() != 4 || b.Distinct() != 2 {
+		t.Fatalf("Unexpected bag state %v (len %d)", b.Counts(), b.Len())
+	}
+	if !b.Remove("banana") || b.Count("banana") != 2 {
+		t.Errorf("Remove should take one banana, got %d", b.Count("banana"))
+	}
+	if b.Remove("cherry") {
+		t.Error("Remove of missing item should report false")
+	}
+	if n := b.RemoveN("banana", 5); n != 2 || b.Count("banana") != 0 || b.Distinct() != 1 {
+		t.Errorf("RemoveN: expected 2 removed and banana gone, got %d %v", n, b.Counts())
+	}
+	if b.Len() != 1 {
+		t.Errorf("Expected len 1, got %d", b.Len())
+	}
+}
+
+func TestBagArithmetic(t *testing.T) {
+	a := NewBag("x", "x", "y")
+	b := NewBag("x", "y", "y", "y", "z")
+
+	tests := []struct {
+		name     string
+		result   *Bag[string]
+		expected map[string]int
+	}{
+		{"sum", a.Sum(b), map[string]int{"x": 3, "y": 4, "z": 1}},
+		{"subtract", a.Subtract(b), map[string]int{"x": 1}},
+		{"reverse subtract", b.Subtract(a), map[string]int{"y": 2, "z": 1}},
+		{"union", a.Union(b), map[string]int{"x": 2, "y": 3, "z": 1}},
+		{"intersection", a.Intersection(b), map[string]int{"x": 1, "y": 1}},
+	}
+	for _, tt := range tests {
+		if got := tt.result.Counts(); !maps.Equal(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+	if got := a.Sum(b).Len(); got != 8 {
+		t.Errorf("Sum len: expected 8, got %d", got)
+	}
+}
+
+func TestBagComparisons(t *testing.T) {
+	a := NewBag(1, 1, 2)
+	if !NewBag(1, 2).IsSubBag(a) || NewBag(1, 1, 1).IsSubBag(a) {
+		t.Error("IsSubBag must respect counts")
+	}
+	if !a.Equal(NewBag(2, 1, 1)) || a.Equal(NewBag(1, 2, 2)) || a.Equal(NewBag(1, 2)) {
+		t.Error("Equal must compare counts")
+	}
+}
+
+func TestRemoveIntersectionMultiset(t *testing.T) {
+	tests := []struct {
+		name     string
+		s1, s2   []string
+		expected []string
+	}{
+		{"one cancels one", []string{"banana", "apple", "banana"}, []string{"banana"}, []string{"apple", "banana"}},
+		{"extra in s2", []string{"banana"}, []string{"banana", "banana"}, nil},
+		{"no duplicates", []string{"apple", "banana", "cherry"}, []string{"banana", "fig"}, []string{"apple", "cherry"}},
+		{"empty s2", []string{"a", "a"}, nil, []string{"a", "a"}},
+	}
+	for _, tt := range tests {
+		if got := RemoveIntersectionMultiset(tt.s1, tt.s2); !slices.Equal(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+
+	// В отличие от обычной разности, повтор в s1 остаётся
+	s1, s2 := []string{"banana", "banana"}, []string{"banana"}
+	if len(RemoveIntersection(s1, s2)) != 0 || len(RemoveIntersectionMultiset(s1, s2)) != 1 {
+		t.Error("Set and multiset modes should differ on duplicates")
+	}
+}
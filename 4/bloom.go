@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+)
+
+var ErrInvalidBloomFilter = errors.New("invalid bloom filter data")
+
+var bloomMagic = [4]byte{'B', 'L', 'M', '1'}
+
+// bloomHeaderSize — магия, число бит m, число хешей k и число добавленных элементов
+const bloomHeaderSize = 4 + 8 + 4 + 8
+
+// maxBloomHashes ограничивает k: оптимальное k = -log2 p, так что 64 хватает
+// для p вплоть до 2^-64, а огромный k из повреждённых данных растянул бы
+// каждую проверку на минуты
+const maxBloomHashes = 64
+
+// maxBloomBits ограничивает размер фильтра 8 ГиБ: без предела огромное число
+// элементов или крошечная вероятность переполнили бы m при переводе из float64
+const maxBloomBits = 1 << 36
+
+// BloomFilter — фильтр Блума для строк. Ложноотрицательных ответов не бывает,
+// ложноположительные возможны с вероятностью, которую оценивает
+// EstimatedFalsePositiveRate. Хеш не зависит от процесса, поэтому фильтр
+// можно сохранить через MarshalBinary и загрузить в другом запуске. Нулевое
+// значение — фильтр без бит: Add только считает строки, MayContain всегда false
+type BloomFilter struct {
+	bits []uint64
+	m    uint64 // число бит
+	k    uint32 // число хеш-функций
+	n    uint64 // сколько элементов добавлено
+}
+
+// NewBloomFilter подбирает размер фильтра под expectedItems элементов и
+// заданную вероятность ложного срабатывания: m = -n·ln p / ln²2, k = m/n·ln 2
+func NewBloomFilter(expectedItems int, fpRate float64) (*BloomFilter, error) {
+	if expectedItems <= 0 {
+		return nil, fmt.Errorf("expected items must be positive, got %d", expectedItems)
+	}
+	if !(fpRate > 0 && fpRate < 1) {
+		return nil, fmt.Errorf("false positive rate must be in (0, 1), got %v", fpRate)
+	}
+	n := float64(expectedItems)
+	bits := math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	if bits > maxBloomBits {
+		return nil, fmt.Errorf("filter for %d items at rate %v needs %.0f bits, limit %d", expectedItems, fpRate, bits, uint64(maxBloomBits))
+	}
+	m := uint64(bits)
+	k := uint32(min(maxBloomHashes, max(1, math.Round(float64(m)/n*math.Ln2))))
+	return &BloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}, nil
+}
+
+// NewBloomFilterFrom строит фильтр по всем строкам items
+func NewBloomFilterFrom(items []string, fpRate float64) (*BloomFilter, error) {
+	f, err := NewBloomFilter(max(len(items), 1), fpRate)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		f.Add(item)
+	}
+	return f, nil
+}
+
+func (f *BloomFilter) Add(s string) {
+	f.n++
+	if f.m == 0 {
+		return
+	}
+	h1, h2 := bloomHashes(s)
+	for i := range uint64(f.k) {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// MayContain возвращает false, если s точно не добавлялась
+func (f *BloomFilter) MayContain(s string) bool {
+	if f.m == 0 {
+		return false
+	}
+	h1, h2 := bloomHashes(s)
+	for i := range uint64(f.k) {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Len возвращает число добавленных строк, включая повторы
+func (f *BloomFilter) Len() int {
+	return int(f.n)
+}
+
+// EstimatedFalsePositiveRate оценивает вероятность ложного срабатывания при
+// текущем заполнении: (1 - e^(-k·n/m))^k. Растёт, если добавлено больше
+// элементов, чем планировалось, и завышена при повторах
+func (f *BloomFilter) EstimatedFalsePositiveRate() float64 {
+	if f.m == 0 {
+		return 0
+	}
+	k := float64(f.k)
+	return math.Pow(1-math.Exp(-k*float64(f.n)/float64(f.m)), k)
+}
+
+// bloomHashes даёт два независимых хеша для двойного хеширования: i-й бит — h1 + i·h2
+func bloomHashes(s string) (uint64, uint64) {
+	h := fnv.New128a()
+	io.WriteString(h, s)
+	sum := h.Sum(nil)
+	h1 := binary.BigEndian.Uint64(sum[:8])
+	h2 := binary.BigEndian.Uint64(sum[8:])
+	// Нечётный шаг не даёт последовательности зациклиться на одном бите
+	return h1, h2 | 1
+}
+
+// MarshalBinary сохраняет фильтр: магия, m, k, n и биты в little-endian
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, bloomHeaderSize, bloomHeaderSize+8*len(f.bits))
+	copy(buf, bloomMagic[:])
+	binary.LittleEndian.PutUint64(buf[4:], f.m)
+	binary.LittleEndian.PutUint32(buf[12:], f.k)
+	binary.LittleEndian.PutUint64(buf[16:], f.n)
+	for _, word := range f.bits {
+		buf = binary.LittleEndian.AppendUint64(buf, word)
+	}
+	return buf, nil
+}
+
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < bloomHeaderSize || [4]byte(data[:4]) != bloomMagic {
+		return ErrInvalidBloomFilter
+	}
+	m := binary.LittleEndian.Uint64(data[4:])
+	k := binary.LittleEndian.Uint32(data[12:])
+	n := binary.LittleEndian.Uint64(data[16:])
+	words := data[bloomHeaderSize:]
+	// (m+63)/64 переполняется при m близком к 2^64, поэтому округляем вверх отдельно
+	wordCount := m / 64
+	if m%64 != 0 {
+		wordCount++
+	}
+	// m = 0 и k = 0 — сохранённое нулевое значение
+	if (m == 0) != (k == 0) || k > maxBloomHashes || len(words)%8 != 0 || uint64(len(words)/8) != wordCount {
+		return ErrInvalidBloomFilter
+	}
+
+	bits := make([]uint64, len(words)/8)
+	for i := range bits {
+		bits[i] = binary.LittleEndian.Uint64(words[i*8:])
+	}
+	*f = BloomFilter{bits: bits, m: m, k: k, n: n}
+	return nil
+}
+
+// RemoveIntersectionApprox убирает из s1 строки, которые могут быть в фильтре,
+// построенном по s2. Строки из s2 в результат не попадают никогда, но каждая
+// строка, которой в s2 нет, может пропасть с вероятностью около fpRate
+func RemoveIntersectionApprox(s1 []string, s2 *BloomFilter) (result []string, fpRate float64) {
+	for _, str := range s1 {
+		if !s2.MayContain(str) {
+			result = append(result, str)
+		}
+	}
+	return result, s2.EstimatedFalsePositiveRate()
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestBloomFilterSizing(t *testing.T) {
+	f, err := NewBloomFilter(1000, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Для p = 1% нужно около 9.6 бит на элемент и 7 хешей
+	if f.m != 9586 || f.k != 7 {
+		t.Errorf("Expected m=9586, k=7, got m=%d, k=%d", f.m, f.k)
+	}
+
+	for _, tt := range []struct {
+		n int
+		p float64
+	}{{0, 0.01}, {-1, 0.01}, {10, 0}, {10, 1}, {10, math.NaN()}, {math.MaxInt, 0.01}, {1 << 32, 1e-300}} {
+		if _, err := NewBloomFilter(tt.n, tt.p); err == nil {
+			t.Errorf("NewBloomFilter(%d, %v): expected error", tt.n, tt.p)
+		}
+	}
+}
+
+// TestBloomFilterFalsePositiveRate проверяет отсутствие ложноотрицательных
+// ответов и долю ложноположительных около целевой
+func TestBloomFilterFalsePositiveRate(t *testing.T) {
+	const n, target = 5000, 0.02
+	f, _ := NewBloomFilter(n, target)
+	for i := range n {
+		f.Add(fmt.Sprintf("blocked-%d", i))
+	}
+	for i := range n {
+		if !f.MayContain(fmt.Sprintf("blocked-%d", i)) {
+			t.Fatalf("False negative for blocked-%d", i)
+		}
+	}
+
+	falsePositives := 0
+	const probes = 50000
+	for i := range probes {
+		if f.MayContain(fmt.Sprintf("allowed-%d", i)) {
+			falsePositives++
+		}
+	}
+	observed := float64(falsePositives) / probes
+	if observed > target*1.5 {
+		t.Errorf("Observed false positive rate %.4f, target %.4f", observed, target)
+	}
+	if est := f.EstimatedFalsePositiveRate(); math.Abs(est-target) > target*0.2 {
+		t.Errorf("Estimated rate %.4f too far from target %.4f", est, target)
+	}
+
+	// Переполненный фильтр сообщает о росте ошибки
+	for i := range n {
+		f.Add(fmt.Sprintf("extra-%d", i))
+	}
+	if est := f.EstimatedFalsePositiveRate(); est < target*3 {
+		t.Errorf("Estimated rate should grow after overfilling, got %.4f", est)
+	}
+}
+
+func TestBloomFilterSerialization(t *testing.T) {
+	f, _ := NewBloomFilterFrom([]string{"banana", "date", "fig"}, 0.001)
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded BloomFilter
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.m != f.m || loaded.k != f.k || loaded.Len() != 3 || !slices.Equal(loaded.bits, f.bits) {
+		t.Fatalf("Loaded filter differs from original")
+	}
+	if !loaded.MayContain("banana") || loaded.EstimatedFalsePositiveRate() != f.EstimatedFalsePositiveRate() {
+		t.Error("Loaded filter answers differently")
+	}
+
+	corrupt := [][]byte{
+		nil,
+		[]byte("XXXX"),
+		append([]byte("BLMX"), data[4:]...),
+		data[:len(data)-1],
+		append(slices.Clone(data), 0),
+		// m = 2^64-1 без слов: (m+63)/64 переполнился бы в ноль
+		withHeader(data[:bloomHeaderSize], math.MaxUint64, f.k),
+		withHeader(data, f.m, maxBloomHashes+1),
+		withHeader(data, f.m, math.MaxUint32),
+		withHeader(data, f.m, 0),
+		withHeader(data[:bloomHeaderSize], 0, f.k),
+	}
+	for i, c := range corrupt {
+		if err := new(BloomFilter).UnmarshalBinary(c); !errors.Is(err, ErrInvalidBloomFilter) {
+			t.Errorf("Corrupt input %d: expected ErrInvalidBloomFilter, got %v", i, err)
+		}
+	}
+}
+
+// withHeader возвращает копию data с подменёнными m и k
+func withHeader(data []byte, m uint64, k uint32) []byte {
+	c := slices.Clone(data)
+	binary.LittleEndian.PutUint64(c[4:], m)
+	binary.LittleEndian.PutUint32(c[12:], k)
+	return c
+}
+
+func TestBloomFilterZeroValue(t *testing.T) {
+	var f BloomFilter
+	f.Add("a")
+	if f.MayContain("a") || f.Len() != 1 || f.EstimatedFalsePositiveRate() != 0 {
+		t.Error("Zero-value filter must stay empty")
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded BloomFilter
+	if err := loaded.UnmarshalBinary(data); err != nil || loaded.MayContain("a") || loaded.Len() != 1 {
+		t.Errorf("Zero-value filter did not round-trip: %v", err)
+	}
+}
+
+func TestRemoveIntersectionApprox(t *testing.T) {
+	slice1 := []string{"apple", "banana", "cherry", "date", "43", "lead", "gno1"}
+	slice2 := []string{"banana", "date", "fig"}
+
+	f, _ := NewBloomFilterFrom(slice2, 1e-6)
+	got, rate := RemoveIntersectionApprox(slice1, f)
+	if expected := RemoveIntersection(slice1, slice2); !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if rate <= 0 || rate > 1e-5 {
+		t.Errorf("Unexpected estimated rate %v", rate)
+	}
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bufio"
+	"container/heap"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	defaultSortMemory = 64 << 20
+	defaultMergeWidth = 64
+	// lineOverhead приближённо учитывает заголовок строки и слот в слайсе
+	lineOverhead = 24
+	runBufSize   = 64 << 10
+)
+
+// ExternalSortOptions задаёт ограничения внешней сортировки
+type ExternalSortOptions struct {
+	// MemoryLimit — сколько байт строк держать в памяти до сброса отсортированного
+	// прогона во временный файл. 0 означает 64 МиБ
+	MemoryLimit int
+	// TempDir — каталог для временных файлов, пустая строка — os.TempDir()
+	TempDir string
+	// MaxMergeWidth — сколько прогонов сливается за один проход. Каждый держит
+	// открытый файл и буфер на 64 КиБ сверх MemoryLimit. Если прогонов больше,
+	// они сливаются в несколько проходов. 0 означает 64, минимум — 2
+	MaxMergeWidth int
+}
+
+// ErrUnsorted возвращается CommSorted, если вход не отсортирован
+var ErrUnsorted = errors.New("input is not sorted")
+
+// ExternalSort сортирует строки из r побайтово и пишет их в w, по одной на строку.
+// Вход делится на прогоны размером до MemoryLimit, каждый сортируется в памяти и
+// сбрасывается во временный файл, затем прогоны сливаются через кучу не более
+// чем по MaxMergeWidth за проход. Если вход умещается в один прогон, временные
+// файлы не создаются
+func ExternalSort(r io.Reader, w io.Writer, opts ExternalSortOptions) error {
+	limit := opts.MemoryLimit
+	if limit <= 0 {
+		limit = defaultSortMemory
+	}
+	width := opts.MaxMergeWidth
+	if width <= 0 {
+		width = defaultMergeWidth
+	}
+	width = max(width, 2)
+
+	var runs []*os.File
+	defer func() {
+		for _, f := range runs {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	br := bufio.NewReaderSize(r, runBufSize)
+	var lines []string
+	used := 0
+	for {
+		line, err := readLine(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line)
+		used += len(line) + lineOverhead
+		if used >= limit {
+			f, err := spillRun(lines, opts.TempDir)
+			if f != nil {
+				runs = append(runs, f)
+			}
+			if err != nil {
+				return err
+			}
+			// clear отпускает сброшенные строки, иначе они жили бы до перезаписи
+			clear(lines)
+			lines, used = lines[:0], 0
+		}
+	}
+
+	slices.Sort(lines)
+	bw := bufio.NewWriterSize(w, runBufSize)
+	if len(runs) == 0 {
+		for _, line := range lines {
+			bw.WriteString(line)
+			bw.WriteByte('\n')
+		}
+		return bw.Flush()
+	}
+
+	// Промежуточные проходы: сливаем самые старые прогоны в новый, пока все
+	// прогоны и остаток в памяти не поместятся в одно слияние
+	for len(runs)+1 > width {
+		merged, err := mergeToTemp(runs[:width], opts.TempDir)
+		if err != nil {
+			return err
+		}
+		for _, f := range runs[:width] {
+			removeTemp(f)
+		}
+		runs = append(runs[width:], merged)
+	}
+
+	// Остаток в памяти участвует в слиянии как ещё один прогон
+	sources, err := runSources(runs)
+	if err != nil {
+		return err
+	}
+	sources = append(sources, &sliceSource{lines: lines})
+
+	err = mergeRuns(sources, func(line string) error {
+		bw.WriteString(line)
+		return bw.WriteByte('\n')
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func runSources(runs []*os.File) ([]lineSource, error) {
+	sources := make([]lineSource, 0, len(runs)+1)
+	for _, f := range runs {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		sources = append(sources, &readerSource{r: bufio.NewReaderSize(f, runBufSize)})
+	}
+	return sources, nil
+}
+
+// mergeToTemp сливает прогоны в новый временный файл
+func mergeToTemp(runs []*os.File, dir string) (*os.File, error) {
+	sources, err := runSources(runs)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(dir, "extsort-*.run")
+	if err != nil {
+		return nil, err
+	}
+	bw := bufio.NewWriterSize(f, runBufSize)
+	err = mergeRuns(sources, func(line string) error {
+		bw.WriteString(line)
+		return bw.WriteByte('\n')
+	})
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		removeTemp(f)
+		return nil, err
+	}
+	return f, nil
+}
+
+// spillRun сортирует прогон и пишет его во временный файл
+func spillRun(lines []string, dir string) (*os.File, error) {
+	slices.Sort(lines)
+	f, err := os.CreateTemp(dir, "extsort-*.run")
+	if err != nil {
+		return nil, err
+	}
+	bw := bufio.NewWriterSize(f, runBufSize)
+	for _, line := range lines {
+		bw.WriteString(line)
+		bw.WriteByte('\n')
+	}
+	return f, bw.Flush()
+}
+
+// readLine читает строку без завершающего '\n'. Последняя строка без перевода
+// строки тоже возвращается; io.EOF — только когда строк больше нет
+func readLine(br *bufio.Reader) (string, error) {
+	line, err := br.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimSuffix(line, "\n"), err
+}
+
+type lineSource interface {
+	next() (string, error)
+}
+
+type readerSource struct {
+	r *bufio.Reader
+}
+
+func (s *readerSource) next() (string, error) {
+	return readLine(s.r)
+}
+
+type sliceSource struct {
+	lines []string
+}
+
+func (s *sliceSource) next() (string, error) {
+	if len(s.lines) == 0 {
+		return "", io.EOF
+	}
+	line := s.lines[0]
+	s.lines = s.lines[1:]
+	return line, nil
+}
+
+type runHead struct {
+	line string
+	src  lineSource
+}
+
+type runHeap []runHead
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return h[i].line < h[j].line }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x any)        { *h = append(*h, x.(runHead)) }
+func (h *runHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// mergeRuns сливает отсортированные источники, передавая строки emit по возрастанию
+func mergeRuns(sources []lineSource, emit func(string) error) error {
+	h := make(runHeap, 0, len(sources))
+	for _, src := range sources {
+		line, err := src.next()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		h = append(h, runHead{line: line, src: src})
+	}
+	heap.Init(&h)
+
+	for h.Len() > 0 {
+		top := &h[0]
+		if err := emit(top.line); err != nil {
+			return err
+		}
+		line, err := top.src.next()
+		switch {
+		case err == io.EOF:
+			heap.Pop(&h)
+		case err != nil:
+			return err
+		default:
+			top.line = line
+			heap.Fix(&h, 0)
+		}
+	}
+	return nil
+}
+
+// CommColumn — колонка вывода в духе утилиты comm
+type CommColumn int
+
+const (
+	OnlyFirst  CommColumn = 1 << iota // строка есть только в первом входе
+	OnlySecond                        // строка есть только во втором входе
+	Common                            // строка есть в обоих
+)
+
+// CommSorted сливает два отсортированных потока строк и передаёт emit каждую
+// строку с её колонкой. Повторы учитываются попарно, как в comm: две строки
+// "x" в первом и одна во втором дают Common и OnlyFirst. Неотсортированный
+// вход обнаруживается и даёт ошибку ErrUnsorted с номером строки
+func CommSorted(first, second io.Reader, emit func(col CommColumn, line string) error) error {
+	a := &checkedSource{r: bufio.NewReaderSize(first, runBufSize), name: "first"}
+	b := &checkedSource{r: bufio.NewReaderSize(second, runBufSize), name: "second"}
+
+	lineA, errA := a.next()
+	lineB, errB := b.next()
+	for {
+		if errA != nil && errA != io.EOF {
+			return errA
+		}
+		if errB != nil && errB != io.EOF {
+			return errB
+		}
+
+		var err error
+		switch {
+		case errA == io.EOF && errB == io.EOF:
+			return nil
+		case errB == io.EOF || errA == nil && lineA < lineB:
+			err = emit(OnlyFirst, lineA)
+			lineA, errA = a.next()
+		case errA == io.EOF || lineB < lineA:
+			err = emit(OnlySecond, lineB)
+			lineB, errB = b.next()
+		default:
+			err = emit(Common, lineA)
+			lineA, errA = a.next()
+			lineB, errB = b.next()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// checkedSource читает строки и проверяет, что они не убывают
+type checkedSource struct {
+	r    *bufio.Reader
+	name string
+	prev string
+	n    int
+}
+
+func (s *checkedSource) next() (string, error) {
+	line, err := readLine(s.r)
+	if err != nil {
+		return "", err
+	}
+	s.n++
+	if s.n > 1 && line < s.prev {
+		return "", fmt.Errorf("%s input, line %d: %w", s.name, s.n, ErrUnsorted)
+	}
+	s.prev = line
+	return line, nil
+}
+
+// ExternalDiff сортирует оба входа внешней сортировкой во временные файлы и
+// сравнивает их через CommSorted. Память ограничена opts.MemoryLimit на время
+// каждой сортировки, на диске нужно место под обе копии и прогоны
+func ExternalDiff(first, second io.Reader, opts ExternalSortOptions, emit func(col CommColumn, line string) error) error {
+	sortedFirst, err := sortToTemp(first, opts)
+	if err != nil {
+		return err
+	}
+	defer removeTemp(sortedFirst)
+
+	sortedSecond, err := sortToTemp(second, opts)
+	if err != nil {
+		return err
+	}
+	defer removeTemp(sortedSecond)
+
+	return CommSorted(sortedFirst, sortedSecond, emit)
+}
+
+func sortToTemp(r io.Reader, opts ExternalSortOptions) (*os.File, error) {
+	f, err := os.CreateTemp(opts.TempDir, "extsort-*.sorted")
+	if err != nil {
+		return nil, err
+	}
+	if err := ExternalSort(r, f, opts); err != nil {
+		removeTemp(f)
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		removeTemp(f)
+		return nil, err
+	}
+	return f, nil
+}
+
+func removeTemp(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// randomLines возвращает n строк с повторами, чтобы проверить и дубликаты
+func randomLines(r *rand.Rand, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("user-%d", r.IntN(n))
+	}
+	return lines
+}
+
+func assertTempDirEmpty(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Temporary files left behind: %v", entries)
+	}
+}
+
+func TestExternalSort(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	lines := randomLines(r, 2000)
+	expected := slices.Sorted(slices.Values(lines))
+
+	// Лимит в 1 КиБ даёт десятки прогонов на диске, большой лимит — сортировку в памяти
+	for _, limit := range []int{1 << 10, 1 << 20} {
+		dir := t.TempDir()
+		var out strings.Builder
+		err := ExternalSort(strings.NewReader(strings.Join(lines, "\n")), &out, ExternalSortOptions{MemoryLimit: limit, TempDir: dir})
+		if err != nil {
+			t.Fatalf("Limit %d: %v", limit, err)
+		}
+		got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		if !slices.Equal(got, expected) {
+			t.Errorf("Limit %d: output is not the sorted input", limit)
+		}
+		assertTempDirEmpty(t, dir)
+	}
+}
+
+// TestExternalSortMultiPass проверяет слияние в несколько проходов при узкой ширине слияния
+func TestExternalSortMultiPass(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	lines := randomLines(r, 2000)
+	expected := slices.Sorted(slices.Values(lines))
+
+	for _, width := range []int{1, 2, 3, 7} {
+		dir := t.TempDir()
+		var out strings.Builder
+		opts := ExternalSortOptions{MemoryLimit: 512, TempDir: dir, MaxMergeWidth: width}
+		if err := ExternalSort(strings.NewReader(strings.Join(lines, "\n")), &out, opts); err != nil {
+			t.Fatalf("Width %d: %v", width, err)
+		}
+		got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		if !slices.Equal(got, expected) {
+			t.Errorf("Width %d: output is not the sorted input", width)
+		}
+		assertTempDirEmpty(t, dir)
+	}
+}
+
+func TestExternalSortEdgeCases(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"", ""},
+		{"b\na\n", "a\nb\n"},
+		{"b\na", "a\nb\n"},
+		{"\n\nx\n", "\n\nx\n"},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		if err := ExternalSort(strings.NewReader(tt.input), &out, ExternalSortOptions{MemoryLimit: 1, TempDir: t.TempDir()}); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != tt.expected {
+			t.Errorf("Input %q: expected %q, got %q", tt.input, tt.expected, out.String())
+		}
+	}
+}
+
+type commLine struct {
+	col  CommColumn
+	line string
+}
+
+func collectComm(t *testing.T, run func(emit func(CommColumn, string) error) error) []commLine {
+	t.Helper()
+	var got []commLine
+	if err := run(func(col CommColumn, line string) error {
+		got = append(got, commLine{col, line})
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestCommSorted(t *testing.T) {
+	first := "apple\nbanana\nbanana\ncherry\n"
+	second := "banana\ndate\n"
+	got := collectComm(t, func(emit func(CommColumn, string) error) error {
+		return CommSorted(strings.NewReader(first), strings.NewReader(second), emit)
+	})
+	expected := []commLine{
+		{OnlyFirst, "apple"},
+		{Common, "banana"},
+		{OnlyFirst, "banana"},
+		{OnlyFirst, "cherry"},
+		{OnlySecond, "date"},
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestCommSortedRejectsUnsorted(t *testing.T) {
+	err := CommSorted(strings.NewReader("a\nb\n"), strings.NewReader("a\nc\nb\n"), func(CommColumn, string) error { return nil })
+	if !errors.Is(err, ErrUnsorted) || !strings.Contains(err.Error(), "second input, line 3") {
+		t.Errorf("Expected ErrUnsorted at second input line 3, got %v", err)
+	}
+}
+
+// TestExternalDiffMatchesMultiset сравнивает результат с разностью в памяти
+func TestExternalDiffMatchesMultiset(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	first, second := randomLines(r, 1500), randomLines(r, 1500)
+	dir := t.TempDir()
+
+	got := collectComm(t, func(emit func(CommColumn, string) error) error {
+		return ExternalDiff(strings.NewReader(strings.Join(first, "\n")), strings.NewReader(strings.Join(second, "\n")),
+			ExternalSortOptions{MemoryLimit: 2 << 10, TempDir: dir}, emit)
+	})
+	assertTempDirEmpty(t, dir)
+
+	columns := make(map[CommColumn][]string)
+	for _, c := range got {
+		columns[c.col] = append(columns[c.col], c.line)
+	}
+	sorted := func(s []string) []string { return slices.Sorted(slices.Values(s)) }
+	if expected := sorted(DifferenceMultiset(first, second)); !slices.Equal(columns[OnlyFirst], expected) {
+		t.Errorf("OnlyFirst differs from multiset difference: %d vs %d lines", len(columns[OnlyFirst]), len(expected))
+	}
+	if expected := sorted(DifferenceMultiset(second, first)); !slices.Equal(columns[OnlySecond], expected) {
+		t.Errorf("OnlySecond differs from multiset difference: %d vs %d lines", len(columns[OnlySecond]), len(expected))
+	}
+	if expected := sorted(DifferenceMultiset(first, columns[OnlyFirst])); !slices.Equal(columns[Common], expected) {
+		t.Errorf("Common differs from multiset intersection: %d vs %d lines", len(columns[Common]), len(expected))
+	}
+}
+
+func TestRunCommArguments(t *testing.T) {
+	if err := runComm([]string{"one"}, "", 1); err == nil {
+		t.Error("Expected error for a single file")
+	}
+	if err := runComm([]string{"a", "b"}, "both", 1); err == nil {
+		t.Error("Expected error for unknown -only column")
+	}
+}
@@ -1,3 +1,5 @@
 module task4
 
 go 1.25.3
+
+require golang.org/x/text v0.40.0
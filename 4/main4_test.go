@@ -6,6 +6,15 @@ import (
 	"testing"
 )
 
+// differenceImpls — реализации разности строковых слайсов, которые должны вести себя одинаково
+var differenceImpls = []struct {
+	name string
+	fn   func(s1, s2 []string) []string
+}{
+	{"RemoveIntersection", RemoveIntersection},
+	{"Difference", Difference[string]},
+}
+
 func TestDifference(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -75,15 +84,17 @@ func TestDifference(t *testing.T) {
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := RemoveIntersection(tt.slice1, tt.slice2)
+	for _, impl := range differenceImpls {
+		for _, tt := range tests {
+			t.Run(impl.name+"/"+tt.name, func(t *testing.T) {
+				result := impl.fn(tt.slice1, tt.slice2)
 
-			if !slices.Equal(result, tt.expected) {
-				t.Errorf("Difference(%v, %v) = %v; expected %v",
-					tt.slice1, tt.slice2, result, tt.expected)
-			}
-		})
+				if !slices.Equal(result, tt.expected) {
+					t.Errorf("Difference(%v, %v) = %v; expected %v",
+						tt.slice1, tt.slice2, result, tt.expected)
+				}
+			})
+		}
 	}
 }
 
@@ -102,43 +113,47 @@ func TestDifferenceUnique(t *testing.T) {
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := RemoveIntersection(tt.slice1, tt.slice2)
+	for _, impl := range differenceImpls {
+		for _, tt := range tests {
+			t.Run(impl.name+"/"+tt.name, func(t *testing.T) {
+				result := impl.fn(tt.slice1, tt.slice2)
 
-			// Сортируем для стабильного сравнения
-			slices.Sort(result)
-			slices.Sort(tt.expected)
+				// Сортируем для стабильного сравнения
+				slices.Sort(result)
+				slices.Sort(tt.expected)
 
-			if !reflect.DeepEqual(result, tt.expected) {
-				t.Errorf("DifferenceUnique(%v, %v) = %v; expected %v",
-					tt.slice1, tt.slice2, result, tt.expected)
-			}
-		})
+				if !reflect.DeepEqual(result, tt.expected) {
+					t.Errorf("DifferenceUnique(%v, %v) = %v; expected %v",
+						tt.slice1, tt.slice2, result, tt.expected)
+				}
+			})
+		}
 	}
 }
 
 func TestDifferenceEdgeCases(t *testing.T) {
-	t.Run("nil slices", func(t *testing.T) {
-		var slice1 []string = nil
-		slice2 := []string{"banana"}
+	for _, impl := range differenceImpls {
+		t.Run(impl.name+"/nil slices", func(t *testing.T) {
+			var slice1 []string = nil
+			slice2 := []string{"banana"}
 
-		result := RemoveIntersection(slice1, slice2)
+			result := impl.fn(slice1, slice2)
 
-		if len(result) != 0 {
-			t.Errorf("Expected empty result, got %v", result)
-		}
-	})
+			if len(result) != 0 {
+				t.Errorf("Expected empty result, got %v", result)
+			}
+		})
 
-	t.Run("slice2 is nil", func(t *testing.T) {
-		slice1 := []string{"apple", "banana"}
-		var slice2 []string = nil
-		expected := []string{"apple", "banana"}
+		t.Run(impl.name+"/slice2 is nil", func(t *testing.T) {
+			slice1 := []string{"apple", "banana"}
+			var slice2 []string = nil
+			expected := []string{"apple", "banana"}
 
-		result := RemoveIntersection(slice1, slice2)
+			result := impl.fn(slice1, slice2)
 
-		if !reflect.DeepEqual(result, expected) {
-			t.Errorf("Expected %v, got %v", expected, result)
-		}
-	})
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("Expected %v, got %v", expected, result)
+			}
+		})
+	}
 }
@@ -1,24 +1,42 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
+	"log"
+	"os"
 )
 
+// RemoveIntersection возвращает элементы s1, которых нет в s2, в исходном порядке
 func RemoveIntersection(s1, s2 []string) []string {
-	slice2Map := make(map[string]bool)
-	var resultSlice []string
-	for _, str := range s2 {
-		slice2Map[str] = true
-	}
-	for _, str := range s1 {
-		if !slice2Map[str] {
-			resultSlice = append(resultSlice, str)
-		}
-	}
-	return resultSlice
+	return Difference(s1, s2)
+}
+
+// RemoveIntersectionFunc сравнивает строки после normalize, например FoldCase
+// или ChainNormalizers(TrimSpace, FoldCase). Элементы s1 возвращаются как есть
+func RemoveIntersectionFunc(s1, s2 []string, normalize func(string) string) []string {
+	return DifferenceFunc(s1, s2, normalize)
+}
+
+// RemoveIntersectionMultiset убирает из s1 столько вхождений строки, сколько
+// их в s2: ["banana", "banana"] минус ["banana"] даёт ["banana"]
+func RemoveIntersectionMultiset(s1, s2 []string) []string {
+	return DifferenceMultiset(s1, s2)
 }
 
 func main() {
+	comm := flag.Bool("comm", false, "сравнить два файла построчно, как comm: task4 -comm first.txt second.txt")
+	only := flag.String("only", "", "для -comm: вывести одну колонку без отступов — first, second или common")
+	memMiB := flag.Int("mem", 64, "для -comm: память на сортировку каждого файла в МиБ")
+	flag.Parse()
+	if *comm {
+		if err := runComm(flag.Args(), *only, *memMiB); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	slice1 := []string{"apple", "banana", "cherry", "date", "43", "lead", "gno1"}
 	slice2 := []string{"banana", "date", "fig"}
 	fmt.Println("Созданы два слайса:")
@@ -29,3 +47,50 @@ func main() {
 	fmt.Println("Создан новый слайс с элементами из первого слайса, которых нет во втором")
 	fmt.Println("Результирующий слайс:", resSlice)
 }
+
+// runComm сравнивает два файла через внешнюю сортировку и печатает результат.
+// Без -only вывод как у comm: первая колонка без отступа, вторая с одним
+// табом, общие строки с двумя
+func runComm(args []string, only string, memMiB int) error {
+	if len(args) != 2 {
+		return fmt.Errorf("-comm expects two files, got %d arguments", len(args))
+	}
+	columns := map[string]CommColumn{"first": OnlyFirst, "second": OnlySecond, "common": Common}
+	var filter CommColumn
+	if only != "" {
+		var ok bool
+		if filter, ok = columns[only]; !ok {
+			return fmt.Errorf("-only must be first, second or common, got %q", only)
+		}
+	}
+
+	first, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer first.Close()
+	second, err := os.Open(args[1])
+	if err != nil {
+		return err
+	}
+	defer second.Close()
+
+	out := bufio.NewWriter(os.Stdout)
+	prefix := map[CommColumn]string{OnlyFirst: "", OnlySecond: "\t", Common: "\t\t"}
+	opts := ExternalSortOptions{MemoryLimit: memMiB << 20}
+	err = ExternalDiff(first, second, opts, func(col CommColumn, line string) error {
+		if filter != 0 {
+			if col != filter {
+				return nil
+			}
+		} else {
+			out.WriteString(prefix[col])
+		}
+		out.WriteString(line)
+		return out.WriteByte('\n')
+	})
+	if err != nil {
+		return err
+	}
+	return out.Flush()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Нормализаторы для RemoveIntersectionFunc. Каждый приводит строку к ключу
+// сравнения; в результат попадают исходные строки, а не ключи
+
+// FoldCase сравнивает строки без учёта регистра (простое свёртывание Unicode:
+// "Banana" == "BANANA", "Ёж" == "ёж"). Полное свёртывание вроде "ß" == "ss" не выполняется
+func FoldCase(s string) string {
+	return strings.Map(foldRune, s)
+}
+
+// foldRune возвращает наименьшую руну из орбиты unicode.SimpleFold
+func foldRune(r rune) rune {
+	least := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		least = min(least, f)
+	}
+	return least
+}
+
+// TrimSpace убирает пробельные символы по краям
+func TrimSpace(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// NormalizeUnicode приводит строку к NFC, так что канонически эквивалентные
+// записи совпадают: "e" + U+0301 == "é", "е" + U+0308 == "ё", а знаки над и под
+// буквой сравниваются независимо от порядка. Совместимые формы ("ﬁ" и "fi")
+// остаются разными
+func NormalizeUnicode(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NumericString приводит целые числа к каноническому виду: "043" == "43",
+// "+7" == "7", "-0" == "0". Строки, не являющиеся целыми числами, не меняются.
+// Длина числа не ограничена, так как разбор не использует strconv
+func NumericString(s string) string {
+	t := strings.TrimSpace(s)
+	sign := ""
+	if t != "" && (t[0] == '+' || t[0] == '-') {
+		if t[0] == '-' {
+			sign = "-"
+		}
+		t = t[1:]
+	}
+	if t == "" || strings.ContainsFunc(t, func(r rune) bool { return r < '0' || r > '9' }) {
+		return s
+	}
+	t = strings.TrimLeft(t, "0")
+	if t == "" {
+		return "0"
+	}
+	return sign + t
+}
+
+// ChainNormalizers применяет нормализаторы слева направо
+func ChainNormalizers(fns ...func(string) string) func(string) string {
+	return func(s string) string {
+		for _, fn := range fns {
+			s = fn(s)
+		}
+		return s
+	}
+}
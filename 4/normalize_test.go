@@ -0,0 +1,87 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizers(t *testing.T) {
+	tests := []struct {
+		name      string
+		normalize func(string) string
+		a, b      string
+		equal     bool
+	}{
+		{"fold latin", FoldCase, "Banana", "bANANA", true},
+		{"fold cyrillic", FoldCase, "Ёжик", "ёЖИК", true},
+		{"fold kelvin sign", FoldCase, "\u212a", "k", true},
+		{"fold keeps letters", FoldCase, "banana", "bananas", false},
+		{"trim", TrimSpace, "  banana\t\n", "banana", true},
+		{"trim keeps inner space", TrimSpace, "ba nana", "banana", false},
+		{"unicode latin", NormalizeUnicode, "Caf\u00e9", "Cafe\u0301", true},
+		{"unicode cyrillic", NormalizeUnicode, "\u0451лка", "\u0435\u0308лка", true},
+		{"unicode short i", NormalizeUnicode, "\u0439од", "\u0438\u0306од", true},
+		{"unicode two marks", NormalizeUnicode, "\u1ec7", "e\u0323\u0302", true},
+		{"unicode mark order", NormalizeUnicode, "e\u0302\u0323", "e\u0323\u0302", true},
+		{"unicode hangul", NormalizeUnicode, "\ud55c", "\u1112\u1161\u11ab", true},
+		{"unicode no base letter", NormalizeUnicode, "x\u0301", "x", false},
+		{"unicode compatibility", NormalizeUnicode, "\ufb01", "fi", false},
+		{"numeric leading zeros", NumericString, "043", "43", true},
+		{"numeric sign", NumericString, "+7", "7", true},
+		{"numeric negative zero", NumericString, "-000", "0", true},
+		{"numeric negative", NumericString, "-043", "43", false},
+		{"numeric huge", NumericString, "000123456789012345678901234567890", "123456789012345678901234567890", true},
+		{"numeric not a number", NumericString, "0x1", "1", false},
+		{"numeric sign only", NumericString, "-", "0", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.normalize(tt.a) == tt.normalize(tt.b); got != tt.equal {
+			t.Errorf("%s: %q vs %q: expected equal=%v, got %q and %q",
+				tt.name, tt.a, tt.b, tt.equal, tt.normalize(tt.a), tt.normalize(tt.b))
+		}
+	}
+}
+
+func TestNumericStringKeepsNonNumbers(t *testing.T) {
+	for _, s := range []string{"", "abc", "1.5", "12a", " 7 x"} {
+		if got := NumericString(s); got != s {
+			t.Errorf("NumericString(%q) = %q; expected unchanged", s, got)
+		}
+	}
+}
+
+func TestRemoveIntersectionFunc(t *testing.T) {
+	userInput := []string{" Banana", "apple", "CHERRY ", "kiwi", "banana"}
+	canonical := []string{"banana", "cherry"}
+
+	got := RemoveIntersectionFunc(userInput, canonical, ChainNormalizers(TrimSpace, FoldCase))
+	// Возвращаются исходные строки пользователя, а не нормализованные ключи
+	if expected := []string{"apple", "kiwi"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	got = RemoveIntersectionFunc([]string{"007", "8", "x"}, []string{"7"}, NumericString)
+	if expected := []string{"8", "x"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	// Тождественная нормализация совпадает с RemoveIntersection
+	identity := func(s string) string { return s }
+	if got := RemoveIntersectionFunc(userInput, canonical, identity); !slices.Equal(got, RemoveIntersection(userInput, canonical)) {
+		t.Errorf("Identity normalization differs from RemoveIntersection: %v", got)
+	}
+}
+
+func TestDifferenceFunc(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	users := []user{{1, "ann"}, {2, "bob"}, {3, "cid"}}
+	banned := []user{{2, "Bob"}}
+	got := DifferenceFunc(users, banned, func(u user) int { return u.ID })
+	if expected := []user{{1, "ann"}, {3, "cid"}}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+// Set — множество значений сравнимого типа. Нулевое значение готово к использованию.
+// Операции над двумя множествами возвращают новое множество и не меняют исходные
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{items: make(map[T]struct{}, len(items))}
+	for _, item := range items {
+		s.items[item] = struct{}{}
+	}
+	return s
+}
+
+func (s *Set[T]) Add(items ...T) {
+	if s.items == nil {
+		s.items = make(map[T]struct{}, len(items))
+	}
+	for _, item := range items {
+		s.items[item] = struct{}{}
+	}
+}
+
+func (s *Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s.items, item)
+	}
+}
+
+func (s *Set[T]) Contains(item T) bool {
+	_, ok := s.items[item]
+	return ok
+}
+
+func (s *Set[T]) Len() int {
+	return len(s.items)
+}
+
+// Values возвращает элементы в произвольном порядке
+func (s *Set[T]) Values() []T {
+	values := make([]T, 0, len(s.items))
+	for item := range s.items {
+		values = append(values, item)
+	}
+	return values
+}
+
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	res := &Set[T]{items: make(map[T]struct{}, len(s.items)+len(other.items))}
+	for item := range s.items {
+		res.items[item] = struct{}{}
+	}
+	for item := range other.items {
+		res.items[item] = struct{}{}
+	}
+	return res
+}
+
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	// Перебираем меньшее множество
+	small, large := s, other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
+	res := NewSet[T]()
+	for item := range small.items {
+		if large.Contains(item) {
+			res.items[item] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Difference возвращает элементы s, которых нет в other
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	res := NewSet[T]()
+	for item := range s.items {
+		if !other.Contains(item) {
+			res.items[item] = struct{}{}
+		}
+	}
+	return res
+}
+
+// SymmetricDifference возвращает элементы, которые есть ровно в одном из множеств
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	res := s.Difference(other)
+	for item := range other.items {
+		if !s.Contains(item) {
+			res.items[item] = struct{}{}
+		}
+	}
+	return res
+}
+
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for item := range s.items {
+		if !other.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// Difference возвращает элементы s1, которых нет в s2, сохраняя порядок
+// и повторы s1. Если таких элементов нет, возвращает nil
+func Difference[T comparable](s1, s2 []T) []T {
+	exclude := NewSet(s2...)
+	var result []T
+	for _, item := range s1 {
+		if !exclude.Contains(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// DifferenceFunc работает как Difference, но сравнивает элементы по ключу key(x).
+// Возвращаются исходные элементы s1
+func DifferenceFunc[T any, K comparable](s1, s2 []T, key func(T) K) []T {
+	exclude := NewSet[K]()
+	for _, item := range s2 {
+		exclude.Add(key(item))
+	}
+	var result []T
+	for _, item := range s1 {
+		if !exclude.Contains(key(item)) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
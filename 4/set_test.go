@@ -0,0 +1,81 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func sortedValues[T interface{ ~int | ~string }](s *Set[T]) []T {
+	values := s.Values()
+	slices.Sort(values)
+	return values
+}
+
+func TestSetBasics(t *testing.T) {
+	var s Set[string]
+	if s.Contains("a") || s.Len() != 0 {
+		t.Fatal("Zero Set should be empty")
+	}
+	s.Add("a", "b", "a")
+	if s.Len() != 2 || !s.Contains("a") || !s.Contains("b") {
+		t.Errorf("Expected {a b}, got %v", s.Values())
+	}
+	s.Remove("a", "missing")
+	if s.Len() != 1 || s.Contains("a") {
+		t.Errorf("Expected {b}, got %v", s.Values())
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+
+	tests := []struct {
+		name     string
+		result   *Set[int]
+		expected []int
+	}{
+		{"union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"intersection", a.Intersection(b), []int{3, 4}},
+		{"difference", a.Difference(b), []int{1, 2}},
+		{"reverse difference", b.Difference(a), []int{5}},
+		{"symmetric difference", a.SymmetricDifference(b), []int{1, 2, 5}},
+		{"intersection with empty", a.Intersection(NewSet[int]()), []int{}},
+	}
+	for _, tt := range tests {
+		if got := sortedValues(tt.result); !slices.Equal(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+
+	// Исходные множества не меняются
+	if !slices.Equal(sortedValues(a), []int{1, 2, 3, 4}) || !slices.Equal(sortedValues(b), []int{3, 4, 5}) {
+		t.Errorf("Operands were modified: %v %v", a.Values(), b.Values())
+	}
+}
+
+func TestSetComparisons(t *testing.T) {
+	small := NewSet("a", "b")
+	large := NewSet("a", "b", "c")
+	empty := NewSet[string]()
+
+	if !small.IsSubset(large) || large.IsSubset(small) {
+		t.Error("IsSubset: {a b} ⊆ {a b c} only")
+	}
+	if !large.IsSuperset(small) || small.IsSuperset(large) {
+		t.Error("IsSuperset: {a b c} ⊇ {a b} only")
+	}
+	if !empty.IsSubset(small) || !small.IsSubset(small) {
+		t.Error("Empty set and the set itself are subsets")
+	}
+	if !small.Equal(NewSet("b", "a")) || small.Equal(large) || small.Equal(NewSet("a", "c")) {
+		t.Error("Equal: unexpected result")
+	}
+}
+
+func TestDifferenceGeneric(t *testing.T) {
+	got := Difference([]int{5, 1, 5, 2, 3}, []int{2, 4})
+	if !slices.Equal(got, []int{5, 1, 5, 3}) {
+		t.Errorf("Expected [5 1 5 3], got %v", got)
+	}
+}